@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/collector"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/jsonmask"
+)
+
+var reconcileDryRun bool
+
+// reconcileCmd represents the reconcile command
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Update existing collector settings to match configuration",
+	Long: `Compares existing collector in portal with configured description, escalation chain,
+resend interval, suppress alert clear, fail back and backup collector, and updates only differing
+settings. Settings which are not configured are left untouched. Use --dry-run to only print the diff.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := initialise(cmd); err != nil {
+			return err
+		}
+		conf.Configured = changedFlags(cmd)
+		err := conf.Validate()
+		if err != nil {
+			return fmt.Errorf("config validation failed with: %w", err)
+		}
+		return mustLMClient()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := commandLogger(cmd)
+		maskedJsonStr, err := jsonmask.MaskJson(conf)
+
+		if err != nil {
+			logger.Warn("Couldn't mask sensitive data of configuration, cannot printing configuration on stdout")
+		} else {
+			logger.Debugf("Configuration: %s", maskedJsonStr)
+		}
+
+		if err := collector.ResolveReferences(logger, conf, lmClient); err != nil {
+			logger.Errorf("%s", err)
+			os.Exit(1)
+		}
+		c, err := collector.FindCollector(conf, lmClient)
+		if err != nil {
+			logger.Errorf("Finding collector failed with: %s", err)
+			os.Exit(1)
+		}
+		if _, err := collector.Reconcile(cmd.Context(), logger, creds, conf, lmClient, c, reconcileDryRun); err != nil {
+			logger.Errorf("%s", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reconcileCmd)
+
+	addStartFlags(reconcileCmd)
+	reconcileCmd.Flags().BoolVar(&reconcileDryRun, "dry-run", false, "Only print settings which would be updated")
+}
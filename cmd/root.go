@@ -6,7 +6,9 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/logicmonitor/lm-sdk-go/client"
@@ -14,19 +16,25 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/client/logicmonitor"
 	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/constants"
 	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/jsonmask"
 )
 
 var (
 	lmClient *client.LMSdkGo = nil
 	creds                    = &config.Creds{}
+	// installDir install root every collector path derives from
+	installDir = constants.DefaultInstallPath
 )
 
 var ExemptCredsCmds = map[string]struct{}{
 	"lm-bootstrap-collector.version":      {},
 	"lm-bootstrap-collector.config.apply": {},
 }
-var logLevel = LogLevel(logrus.InfoLevel)
+var (
+	logLevel  = LogLevel(logrus.InfoLevel)
+	logFormat = TextLogFormat
+)
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -42,6 +50,11 @@ to quickly create a Cobra application.`,
 		if err := initialise(cmd); err != nil {
 			return err
 		}
+		constants.SetInstallPath(installDir)
+		if f := cmd.Flags().Lookup("install-report"); f != nil && !f.Changed {
+			// default report path follows install root
+			conf.InstallReportPath = constants.InstallReportPath
+		}
 		err := creds.Validate()
 		if err != nil {
 			if _, ok := ExemptCredsCmds[getCmdFqnm(cmd)]; !ok {
@@ -49,11 +62,11 @@ to quickly create a Cobra application.`,
 			}
 		}
 
-		logrus.SetOutput(cmd.OutOrStdout())
+		logrus.SetOutput(logOutput(cmd))
 		if logrus.Level(logLevel) > logrus.DebugLevel {
 			logrus.SetReportCaller(true)
 		}
-		logrus.SetFormatter(&logrus.TextFormatter{TimestampFormat: time.RFC3339, FullTimestamp: true})
+		logrus.SetFormatter(logFormat.Formatter())
 		logrus.SetLevel(logrus.Level(logLevel))
 		logger := commandLogger(cmd)
 
@@ -79,6 +92,14 @@ to quickly create a Cobra application.`,
 	// Run: func(cmd *cobra.Command, args []string) { },
 }
 
+// logOutput writer of logs, stderr when command prints machine readable output on stdout
+func logOutput(cmd *cobra.Command) io.Writer {
+	if f := cmd.Flags().Lookup("output"); f != nil && f.Value.String() == outputJSON {
+		return cmd.ErrOrStderr()
+	}
+	return cmd.OutOrStdout()
+}
+
 func commandLogger(cmd *cobra.Command) logrus.FieldLogger {
 	return logrus.WithField("command", getCmdFqnm(cmd))
 }
@@ -101,18 +122,24 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().Var(&logLevel, "log-level", "Log Level")
+	rootCmd.PersistentFlags().Var(&logFormat, "log-format", "Log Format (text or json)")
 	rootCmd.PersistentFlags().StringVar(&creds.Account, "account", "", "Logicmonitor Account")
 	rootCmd.PersistentFlags().StringVar(&creds.AccessID, "access-id", "", "Logicmonitor Access ID")
 	rootCmd.PersistentFlags().StringVar(&creds.AccessKey, "access-key", "", "Logicmonitor Access Key")
 	rootCmd.PersistentFlags().StringVar(&creds.ProxyUrl, "proxy-url", "", "ProxyUrl")
 	rootCmd.PersistentFlags().StringVar(&creds.ProxyUser, "proxy-user", "", "ProxyUser")
 	rootCmd.PersistentFlags().StringVar(&creds.ProxyPass, "proxy-pass", "", "ProxyPass")
+	rootCmd.PersistentFlags().StringVar(&creds.NoProxy, "no-proxy", "", "Comma separated hosts collector reaches without proxy, written into agent.conf proxy.exclude")
 	rootCmd.PersistentFlags().BoolVar(&creds.IgnoreSSL, "ignore-ssl", false, "IgnoreSSL")
 	rootCmd.PersistentFlags().StringVar(&creds.SudoPass, "sudo-pass", "", "Sudo Password")
+	rootCmd.PersistentFlags().StringVar(&installDir, "install-dir", constants.DefaultInstallPath, "Collector install root, e.g. on a mounted volume")
 
 	_ = rootCmd.RegisterFlagCompletionFunc("log-level", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"info", "debug", "trace", "warn", "warning", "error", "fatal", "panic"}, cobra.ShellCompDirectiveDefault
 	})
+	_ = rootCmd.RegisterFlagCompletionFunc("log-format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"text", "json"}, cobra.ShellCompDirectiveDefault
+	})
 }
 
 type LogLevel logrus.Level
@@ -134,6 +161,40 @@ func (ll *LogLevel) String() string {
 	return logrus.Level(*ll).String()
 }
 
+type LogFormat string
+
+const (
+	TextLogFormat LogFormat = "text"
+	JsonLogFormat LogFormat = "json"
+)
+
+func (lf *LogFormat) Set(v string) error {
+	switch LogFormat(strings.ToLower(v)) {
+	case TextLogFormat:
+		*lf = TextLogFormat
+	case JsonLogFormat:
+		*lf = JsonLogFormat
+	default:
+		return fmt.Errorf(`must be one of "text" or "json"`)
+	}
+	return nil
+}
+
+func (lf *LogFormat) Type() string {
+	return "LogFormat"
+}
+
+func (lf *LogFormat) String() string {
+	return string(*lf)
+}
+
+func (lf *LogFormat) Formatter() logrus.Formatter {
+	if *lf == JsonLogFormat {
+		return &logrus.JSONFormatter{TimestampFormat: time.RFC3339}
+	}
+	return &logrus.TextFormatter{TimestampFormat: time.RFC3339, FullTimestamp: true}
+}
+
 func mustLMClient() error {
 	if lmClient == nil {
 		return fmt.Errorf("logicmonitor client couldn't create, check credentials")
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/collector"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/health"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/jsonmask"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/logstream"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/metrics"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/supervisor"
+)
+
+var runConf = &config.RunConfig{}
+
+// runCmd represents the run command
+var runCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Bootstrap collector and supervise agent and watchdog processes",
+	Long: `Runs whole collector container lifecycle: installs collector (same as start),
+applies collector-conf on agent.conf, starts watchdog and keeps watching agent and watchdog
+pid files. When either process stays dead beyond its failure threshold, restarts it with exponential
+backoff up to --restart-limit times and exits with non-zero status when it still doesn't come up.
+When --health-addr is set, serves /healthz and /readyz for kubernetes probes and
+prometheus metrics on /metrics.
+Streams wrapper, watchdog and sbproxy logs with "source" field set to log file name.
+On SIGTERM / SIGINT, stops agent through sbshutdown and runs shutdown (same as shutdown command).
+With --process-user, agent and watchdog service scripts and sbshutdown run as that user while
+installation, configuration and shutdown cleanup run as the user lmbc run runs as (e.g. root under sudo).
+
+Exit codes:
+  0 - stopped cleanly on SIGTERM / SIGINT
+  1 - failure
+  2 - bootstrap (start / config apply) failed
+  3 - agent crashed, collector cleanup skipped
+  4 - watchdog crashed, collector cleanup skipped
+  5 - stopped on SIGTERM / SIGINT but stopping agent or collector cleanup failed, or bootstrap
+      didn't finish within --shutdown-timeout of the signal`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := initialise(cmd); err != nil {
+			return err
+		}
+		conf.Configured = changedFlags(cmd)
+		if err := runConf.Validate(); err != nil {
+			return fmt.Errorf("run config validation failed with: %w", err)
+		}
+		if err := conf.Validate(); err != nil {
+			return fmt.Errorf("config validation failed with: %w", err)
+		}
+		if runConf.SkipBootstrap {
+			if conf.Cleanup {
+				return mustLMClient()
+			}
+			return nil
+		}
+		if err := initialiseConf(cmd); err != nil {
+			return err
+		}
+		return mustLMClient()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := commandLogger(cmd)
+		maskedJsonStr, err := jsonmask.MaskJson(conf)
+
+		if err != nil {
+			logger.Warn("Couldn't mask sensitive data of configuration, cannot printing configuration on stdout")
+		} else {
+			logger.Debugf("Configuration: %s", maskedJsonStr)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+		code := runCollector(ctx, logger)
+		stop()
+		os.Exit(code)
+	},
+}
+
+// runCollector bootstraps and supervises collector until it crashes or a termination signal is received,
+// returns exit code describing which path was taken
+func runCollector(ctx context.Context, logger logrus.FieldLogger) int {
+	s := supervisor.New(logger, runConf)
+	state := &health.State{}
+	if runConf.HealthAddr != "" {
+		hs := health.NewServer(logger, runConf.HealthAddr, state, s.Agent, s.Watchdog)
+		hs.Handle("/metrics", metrics.Handler())
+		hs.Start()
+		defer func() {
+			stopCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = hs.Stop(stopCtx)
+		}()
+	}
+
+	// bootstrap steps such as installer don't observe termination signal, don't let them hold container hostage
+	disarm := armShutdownWatchdog(ctx, logger, runConf.ShutdownTimeout)
+	defer disarm()
+	collectorID := conf.ID
+	if !runConf.SkipBootstrap {
+		c, _, err := collector.Start(ctx, logger, creds, conf, lmClient)
+		if err != nil {
+			logger.Errorf("Install failed with: %s", err)
+			return supervisor.ExitBootstrapFailed
+		}
+		collectorID = c.ID
+		if len(collectorConf.AgentConf) > 0 {
+			if err := collector.Apply(logger, collectorConf); err != nil {
+				logger.Errorf("Applying collector configuration failed with: %s", err)
+				return supervisor.ExitBootstrapFailed
+			}
+		}
+	}
+	disarm()
+	state.SetBootstrapped(collectorID)
+
+	var err error
+	if ctx.Err() == nil {
+		err = s.Start(ctx)
+	} else {
+		// termination signal arrived during bootstrap, don't start watchdog just to stop it again
+		logger.Info("Received termination signal during bootstrap, skipping watchdog start")
+	}
+	if err == nil && ctx.Err() == nil {
+		if runConf.StreamLogs {
+			logsCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			go logstream.Follow(logsCtx, logger, time.Second, runConf.LogFiles()...)
+		}
+		err = s.Watch(ctx)
+	}
+	if err != nil && ctx.Err() == nil {
+		// we want to skip cleanup since the collector failed unexpectedly
+		logger.Errorf("%s, skipping collector cleanup\nExiting", err)
+		if code := supervisor.ExitCode(err); code != supervisor.ExitFailure {
+			return code
+		}
+		return supervisor.ExitWatchdogCrashed
+	}
+
+	logger.Info("Received termination signal, shutting down")
+	code := supervisor.ExitOK
+	if err := s.Stop(); err != nil {
+		logger.Errorf("%s", err)
+		code = supervisor.ExitShutdownFailed
+	}
+	if err := collector.Shutdown(logger, conf, lmClient); err != nil {
+		logger.Errorf("Shutdown failed with: %s", err)
+		code = supervisor.ExitShutdownFailed
+	}
+	return code
+}
+
+// armShutdownWatchdog exits process with shutdown failed status when bootstrap doesn't finish within timeout
+// of termination signal. Returned func disarms watchdog, stopping agent has shutdown timeout on its own.
+func armShutdownWatchdog(ctx context.Context, logger logrus.FieldLogger, timeout time.Duration) func() {
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+		}
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-done:
+		case <-timer.C:
+			logger.Errorf("Bootstrap didn't finish within %s of termination signal, skipping collector cleanup\nExiting", timeout)
+			os.Exit(supervisor.ExitShutdownFailed)
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+		})
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+
+	addStartFlags(runCmd)
+	runCmd.Flags().BoolVar(&runConf.SkipBootstrap, "skip-bootstrap", false, "Skip start and config apply, only supervise already installed collector")
+	runCmd.Flags().DurationVar(&runConf.PollInterval, "poll-interval", 10*time.Second, "Interval between agent and watchdog liveness checks")
+	runCmd.Flags().DurationVar(&runConf.WatchdogStartTimeout, "watchdog-start-timeout", 10*time.Second, "Time to wait for watchdog pid file after starting watchdog")
+	runCmd.Flags().IntVar(&runConf.AgentFailureThreshold, "agent-failure-threshold", 6, "Consecutive failed agent checks after which container exits")
+	runCmd.Flags().IntVar(&runConf.WatchdogFailureThreshold, "watchdog-failure-threshold", 24, "Consecutive failed watchdog checks after which container exits")
+	runCmd.Flags().IntVar(&runConf.RestartLimit, "restart-limit", 3, "Restart attempts of agent or watchdog before container exits (0 disables restarts)")
+	runCmd.Flags().DurationVar(&runConf.RestartBackoff, "restart-backoff", 10*time.Second, "Initial backoff before restarting agent or watchdog, doubled on every attempt")
+	runCmd.Flags().DurationVar(&runConf.RestartMaxBackoff, "restart-max-backoff", 5*time.Minute, "Maximum backoff between restarts, process running longer than this gets full restart attempts again")
+	runCmd.Flags().BoolVar(&runConf.StreamLogs, "stream-logs", true, "Stream wrapper, watchdog and sbproxy logs on stdout")
+	runCmd.Flags().StringVar(&runConf.ProcessUser, "process-user", "", "User agent and watchdog are started, restarted and stopped (sbshutdown) as, e.g. when run itself runs under sudo (default current user)")
+	runCmd.Flags().StringVar(&runConf.HealthAddr, "health-addr", "", "Address to serve /healthz and /readyz probes and /metrics on, e.g. :8080 (disabled when empty)")
+	runCmd.Flags().DurationVar(&runConf.ShutdownTimeout, "shutdown-timeout", 30*time.Second, "Grace period to stop agent on termination signal before collector cleanup, and for bootstrap in progress to finish")
+}
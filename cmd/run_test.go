@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/constants"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/supervisor"
+)
+
+func TestShutdownWatchdogFires(t *testing.T) {
+	if os.Getenv("LMBC_TEST_HUNG_BOOTSTRAP") == "1" {
+		logger := logrus.New()
+		logger.SetOutput(io.Discard)
+		ctx, cancel := context.WithCancel(context.Background())
+		armShutdownWatchdog(ctx, logger, 10*time.Millisecond)
+		cancel()
+		// hung bootstrap
+		time.Sleep(10 * time.Second)
+		return
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=^TestShutdownWatchdogFires$")
+	cmd.Env = append(os.Environ(), "LMBC_TEST_HUNG_BOOTSTRAP=1")
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != supervisor.ExitShutdownFailed {
+		t.Errorf("hung bootstrap exited with %v, want exit status %d", err, supervisor.ExitShutdownFailed)
+	}
+}
+
+func TestShutdownWatchdogDisarmedInTime(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	ctx, cancel := context.WithCancel(context.Background())
+	disarm := armShutdownWatchdog(ctx, logger, 50*time.Millisecond)
+	cancel()
+	disarm()
+	// disarming twice, as deferred disarm does, must not panic
+	disarm()
+	// process would have exited by now if watchdog still fired
+	time.Sleep(100 * time.Millisecond)
+}
+
+func TestRunSkipsWatchdogStartAfterSignalDuringBootstrap(t *testing.T) {
+	dir := t.TempDir()
+	constants.SetInstallPath(dir)
+	defer constants.SetInstallPath("")
+	if err := os.MkdirAll(constants.LockPath, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	calls := filepath.Join(dir, "calls")
+	for _, bin := range []string{constants.AgentBin, constants.WatchdogBin, constants.SbShutdownBin} {
+		script := "#!/bin/sh\necho \"$(basename $0) $@\" >> " + calls + "\n"
+		if err := os.WriteFile(bin, []byte(script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	savedRunConf, savedConf := runConf, conf
+	defer func() {
+		runConf, conf = savedRunConf, savedConf
+	}()
+	runConf = &config.RunConfig{SkipBootstrap: true, ShutdownTimeout: time.Second}
+	conf = &config.Config{}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if code := runCollector(ctx, logger); code != supervisor.ExitOK {
+		t.Errorf("runCollector() = %d, want %d", code, supervisor.ExitOK)
+	}
+	b, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(b); got != "sbshutdown \n" {
+		t.Errorf("service scripts called: %q, want only sbshutdown", got)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/collector"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
 	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/jsonmask"
 )
 
@@ -60,7 +61,7 @@ func init() {
 	shutdownCmd.Flags().VarP(&conf.Size, "collector-size", "", "Collector Size")
 	shutdownCmd.Flags().BoolVar(&conf.Cleanup, "cleanup", false, "Cleanup")
 	shutdownCmd.Flags().StringVar(&conf.Group, "collector-group", "", "Group")
-	shutdownCmd.Flags().Int32Var(&conf.Version, "version", 0, "Version")
+	shutdownCmd.Flags().StringVar(&conf.VersionSpec, "version", "", "Collector version or constraint, same as start")
 	shutdownCmd.Flags().StringVar(&conf.Description, "description", "", "Description")
 	shutdownCmd.Flags().BoolVar(&conf.EnableFailBack, "enable-fail-back", false, "EnableFailBack")
 	shutdownCmd.Flags().Int32Var(&conf.EscalatingChainID, "escalating-chain-id", 0, "EscalatingChainID")
@@ -69,6 +70,11 @@ func init() {
 	shutdownCmd.Flags().BoolVar(&conf.SuppressAlertClear, "suppress-alert-clear", false, "SuppressAlertClear")
 	shutdownCmd.Flags().BoolVar(&conf.UseEa, "use-ea", false, "UseEa")
 	shutdownCmd.Flags().BoolVar(&conf.Kubernetes, "kubernetes", false, "Kubernetes")
+	shutdownCmd.Flags().BoolVar(&conf.AutoRegister, "auto-register", false, "In kubernetes, register collector per statefulset replica instead of using ids")
+
+	shutdownCmd.Flags().StringVar(&conf.InstanceID, "instance-id", "", "Instance ID used to find collector")
+	shutdownCmd.Flags().StringVar(&conf.InstanceIDSource, "instance-id-source", "", "Derive instance ID from \"machine-id\" or \"pod-uid\" (POD_UID env) when instance-id is not set")
+	shutdownCmd.Flags().StringVar(&conf.InstanceIDProperty, "instance-id-property", config.DefaultInstanceIDProperty, "Collector custom property holding instance ID")
 
 	shutdownCmd.Flags().StringVar(&conf.IDS, "ids", "", "IDS")
 	shutdownCmd.Flags().BoolVar(&conf.Debug, "debug", false, "Debug")
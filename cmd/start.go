@@ -5,12 +5,20 @@ Copyright © 2022 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
 
+	"github.com/logicmonitor/lm-sdk-go/models"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/collector"
 	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/constants"
 	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/jsonmask"
 )
 
@@ -35,10 +43,14 @@ to quickly create a Cobra application.`,
 		if err := initialise(cmd); err != nil {
 			return err
 		}
+		conf.Configured = changedFlags(cmd)
 		err := conf.Validate()
 		if err != nil {
 			return fmt.Errorf("config validation failed with: %w", err)
 		}
+		if startOutput != outputText && startOutput != outputJSON {
+			return fmt.Errorf(`unknown output format %q, must be one of "%s" or "%s"`, startOutput, outputText, outputJSON)
+		}
 		return mustLMClient()
 	},
 	Run: func(cmd *cobra.Command, args []string) {
@@ -51,13 +63,72 @@ to quickly create a Cobra application.`,
 			logger.Debugf("Configuration: %s", maskedJsonStr)
 		}
 
-		if err := collector.Start(logger, creds, conf, lmClient); err != nil {
+		c, report, err := collector.Start(cmd.Context(), logger, creds, conf, lmClient)
+		if startOutput == outputJSON {
+			printInstallReport(cmd.OutOrStdout(), logger, c, report, err)
+		}
+		if err != nil {
 			logger.Infof("Install failed with: %s", err)
+			// pipelines waiting for collector, pinning version or reading json report rely on exit status
+			if conf.Wait || conf.StrictVersion || startOutput == outputJSON {
+				os.Exit(1)
+			}
 			return
 		}
+		if conf.Wait && !conf.SkipInstall {
+			if _, err := collector.WaitReady(cmd.Context(), logger, conf, lmClient, c.ID, collector.ExpectedBuild(conf, c)); err != nil {
+				logger.Errorf("%s", err)
+				os.Exit(1)
+			}
+		}
 	},
 }
 
+// Output formats of start command
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+var startOutput string
+
+// printInstallReport prints install report on out. Without report of this run, failure of this run is reported
+// with its error, and report of earlier installation is printed when collector was already installed.
+func printInstallReport(out io.Writer, logger logrus.FieldLogger, c *models.Collector, report *collector.InstallReport, err error) {
+	if report == nil && err != nil {
+		report = failureReport(c, err)
+	}
+	if report == nil && conf.InstallReportPath != "" {
+		report, _ = collector.ReadInstallReport(conf.InstallReportPath)
+	}
+	if report == nil {
+		report = &collector.InstallReport{}
+	}
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		logger.Errorf("Marshalling install report failed with: %s", err)
+		return
+	}
+	_, _ = fmt.Fprintln(out, string(b))
+}
+
+// failureReport report of run failed before installer ran
+func failureReport(c *models.Collector, err error) *collector.InstallReport {
+	report := &collector.InstallReport{
+		InstallUser:      conf.InstallUser,
+		RequestedVersion: conf.VersionSpec,
+		StartedAt:        time.Now(),
+		Error:            err.Error(),
+	}
+	if report.RequestedVersion == "" && conf.Version != 0 {
+		report.RequestedVersion = strconv.Itoa(int(conf.Version))
+	}
+	if c != nil {
+		report.CollectorID = c.ID
+	}
+	return report
+}
+
 func init() {
 	rootCmd.AddCommand(startCmd)
 
@@ -70,30 +141,11 @@ func init() {
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	// rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
-	startCmd.Flags().Int32Var(&conf.BackupCollectorID, "backup-collector-id", 0, "Backup Collector ID")
-	startCmd.Flags().VarP(&conf.Size, "size", "", "Collector Size")
-	startCmd.Flags().BoolVar(&conf.Cleanup, "cleanup", false, "Cleanup")
-	startCmd.Flags().StringVar(&conf.Group, "group", "", "Group")
-	startCmd.Flags().Int32Var(&conf.Version, "version", 0, "Version")
-	startCmd.Flags().StringVar(&conf.Description, "description", "", "Description")
-	startCmd.Flags().BoolVar(&conf.EnableFailBack, "enable-fail-back", false, "EnableFailBack")
-	startCmd.Flags().Int32Var(&conf.EscalatingChainID, "escalating-chain-id", 0, "EscalatingChainID")
-	startCmd.Flags().Int32Var(&conf.ID, "id", 0, "ID")
-	startCmd.Flags().Int32Var(&conf.ResendInterval, "resend-interval", 0, "ResendInterval")
-	startCmd.Flags().BoolVar(&conf.SuppressAlertClear, "suppress-alert-clear", false, "SuppressAlertClear")
-	startCmd.Flags().BoolVar(&conf.UseEa, "use-ea", false, "UseEa")
-	startCmd.Flags().BoolVar(&conf.Kubernetes, "kubernetes", false, "Kubernetes")
-
-	startCmd.Flags().StringVar(&conf.IDS, "ids", "", "IDS")
-	startCmd.Flags().BoolVar(&conf.Debug, "debug", false, "Debug")
-	startCmd.Flags().IntVar(&conf.DebugIndex, "debug-index", 0, "Debug Index")
-	startCmd.Flags().BoolVar(&conf.SkipInstall, "skip-install", false, "Skip Install (only download)")
-	startCmd.Flags().BoolVar(&conf.RunAsSudo, "run-as-sudo", false, "Run As Sudo")
-	startCmd.Flags().StringVar(&conf.InstallUser, "install-user", "logicmonitor", "Install User")
-
-	_ = startCmd.RegisterFlagCompletionFunc("size", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{"nano", "small", "medium", "large", "extra_large", "double_extra_large"}, cobra.ShellCompDirectiveDefault
-	})
+	addStartFlags(startCmd)
+	startCmd.Flags().StringVarP(&startOutput, "output", "o", outputText, "Output format, \"json\" prints install report on stdout")
+	startCmd.Flags().BoolVar(&conf.Wait, "wait", false, "Wait for collector to be up in portal with installed build, exit with non-zero status on timeout")
+	addWaitFlags(startCmd)
+
 	// Here you will define your flags and configuration settings.
 
 	// Cobra supports Persistent Flags which will work for this command
@@ -105,6 +157,66 @@ func init() {
 	// startCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
 
+// addStartFlags registers collector bootstrap flags, shared by commands which run collector.Start
+func addStartFlags(cmd *cobra.Command) {
+	cmd.Flags().Int32Var(&conf.BackupCollectorID, "backup-collector-id", 0, "Backup Collector ID")
+	cmd.Flags().StringVar(&conf.BackupCollector, "backup-collector", "", "Backup collector description, resolved to backup-collector-id")
+	cmd.Flags().StringVar(&conf.BackupCollectorProperty, "backup-collector-property", "", "Backup collector custom property in name=value format, resolved to backup-collector-id")
+	cmd.Flags().VarP(&conf.Size, "size", "", "Collector Size")
+	cmd.Flags().BoolVar(&conf.Cleanup, "cleanup", false, "Cleanup")
+	cmd.Flags().StringVar(&conf.Group, "group", "", "Group")
+	cmd.Flags().Int32Var(&conf.GroupID, "group-id", 0, "Group ID, takes precedence over group name")
+	cmd.Flags().BoolVar(&conf.CreateGroup, "create-group", false, "Create collector group when it doesn't exist")
+	cmd.Flags().StringVar(&conf.GroupDescription, "group-description", "", "Description of created collector group")
+	cmd.Flags().StringToStringVar(&conf.GroupProperties, "group-properties", nil, "Custom properties of created collector group, e.g. env=prod,team=infra")
+	cmd.Flags().StringVar(&conf.VersionSpec, "version", "", "Collector version (34001 or 34.001) or constraint resolved against available versions, e.g. \">=34.000 <35.000\", bare major version 34 means any 34.xxx")
+	cmd.Flags().StringVar(&conf.Channel, "channel", "", "Release channel versions are picked from: ga, ea or mgd (default ea with use-ea, ga otherwise)")
+	cmd.Flags().BoolVar(&conf.StrictVersion, "strict-version", false, "Fail instead of falling back to another version when requested version isn't available, or installed version differs from requested one")
+	cmd.Flags().StringVar(&conf.Description, "description", "", "Description, go template with .Hostname, .Index, .Env, .Size and .Group, e.g. {{.Env.CLUSTER}}-{{.Hostname}}-{{.Index}}")
+	cmd.Flags().StringToStringVar(&conf.Properties, "properties", nil, "Custom properties of created collector, values are go templates like description, e.g. cluster={{.Env.CLUSTER}}")
+	cmd.Flags().BoolVar(&conf.EnableFailBack, "enable-fail-back", false, "EnableFailBack")
+	cmd.Flags().Int32Var(&conf.EscalatingChainID, "escalating-chain-id", 0, "EscalatingChainID")
+	cmd.Flags().StringVar(&conf.EscalatingChain, "escalating-chain", "", "Escalation chain name, resolved to escalating-chain-id")
+	cmd.Flags().Int32Var(&conf.ID, "id", 0, "ID")
+	cmd.Flags().Int32Var(&conf.ResendInterval, "resend-interval", 0, "ResendInterval")
+	cmd.Flags().BoolVar(&conf.SuppressAlertClear, "suppress-alert-clear", false, "SuppressAlertClear")
+	cmd.Flags().BoolVar(&conf.UseEa, "use-ea", false, "UseEa")
+	cmd.Flags().StringVar(&conf.InstallerPath, "installer-path", "", "Install from local installer file instead of downloading it from portal")
+	cmd.Flags().StringVar(&conf.InstallerURL, "installer-url", "", "Download installer from mirror url instead of portal")
+	cmd.Flags().StringVar(&conf.InstallerUser, "installer-user", "", "Basic auth user of installer mirror")
+	cmd.Flags().StringVar(&conf.InstallerPass, "installer-pass", "", "Basic auth password of installer mirror")
+	cmd.Flags().StringVar(&conf.InstallerCABundle, "installer-ca-bundle", "", "PEM CA bundle to verify installer mirror certificate")
+	cmd.Flags().StringVar(&conf.OsArch, "os-arch", "", "Installer platform: Linux64, Linux32, Win64 or Win32 (default selected from host os and architecture), platform not runnable on host requires skip-install")
+	cmd.Flags().StringVar(&conf.InstallReportPath, "install-report", constants.InstallReportPath, "Path install report is written to as json (disabled when empty)")
+	cmd.Flags().StringVar(&conf.InstallerCacheDir, "installer-cache-dir", constants.InstallerCachePath, "Directory caching downloaded installers by version, size, EA flag and arch, e.g. on a mounted volume (disabled when empty)")
+	cmd.Flags().Int64Var(&conf.InstallerCacheSize, "installer-cache-size", 2<<30, "Size budget of installer cache in bytes, least recently used installers are evicted beyond it")
+	cmd.Flags().BoolVar(&conf.Kubernetes, "kubernetes", false, "Kubernetes")
+	cmd.Flags().BoolVar(&conf.AutoRegister, "auto-register", false, "In kubernetes, register collector per statefulset replica instead of using ids")
+
+	cmd.Flags().BoolVar(&conf.CreateDevice, "create-device", false, "Auto create collector device to monitor collector itself")
+	cmd.Flags().StringVar(&conf.DeviceGroup, "device-group", "", "Full path of device group collector device lands in, e.g. Collectors/prod")
+	cmd.Flags().Int32Var(&conf.DeviceGroupID, "device-group-id", 0, "Device group ID collector device lands in, takes precedence over device-group")
+	cmd.Flags().StringVar(&conf.DeviceDisplayName, "device-display-name", "", "Collector device display name template, e.g. {{.Hostname}}-collector-{{.CollectorID}}")
+	cmd.Flags().StringSliceVar(&conf.DeviceCategories, "device-categories", nil, "system.categories of collector device")
+	cmd.Flags().StringToStringVar(&conf.DeviceProperties, "device-properties", nil, "Custom properties of collector device, e.g. env=prod,team=infra")
+	cmd.Flags().DurationVar(&conf.DeviceWaitTimeout, "device-wait-timeout", 2*time.Minute, "Time to wait for collector device to be created")
+
+	cmd.Flags().StringVar(&conf.InstanceID, "instance-id", "", "Instance ID stamped on created collector as custom property and used to find it again")
+	cmd.Flags().StringVar(&conf.InstanceIDSource, "instance-id-source", "", "Derive instance ID from \"machine-id\" or \"pod-uid\" (POD_UID env) when instance-id is not set")
+	cmd.Flags().StringVar(&conf.InstanceIDProperty, "instance-id-property", config.DefaultInstanceIDProperty, "Collector custom property holding instance ID")
+
+	cmd.Flags().StringVar(&conf.IDS, "ids", "", "IDS")
+	cmd.Flags().BoolVar(&conf.Debug, "debug", false, "Debug")
+	cmd.Flags().IntVar(&conf.DebugIndex, "debug-index", 0, "Debug Index")
+	cmd.Flags().BoolVar(&conf.SkipInstall, "skip-install", false, "Skip Install (only download)")
+	cmd.Flags().BoolVar(&conf.RunAsSudo, "run-as-sudo", false, "Run As Sudo")
+	cmd.Flags().StringVar(&conf.InstallUser, "install-user", "logicmonitor", "Install User")
+
+	_ = cmd.RegisterFlagCompletionFunc("size", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"nano", "small", "medium", "large", "extra_large", "double_extra_large"}, cobra.ShellCompDirectiveDefault
+	})
+}
+
 func initialise(cmd *cobra.Command) error {
 	v := viper.New()
 
@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/logicmonitor/lm-sdk-go/models"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/collector"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+)
+
+func TestJSONOutputKeepsLogsOffStdout(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	cmd := &cobra.Command{Use: "start"}
+	cmd.Flags().StringP("output", "o", outputText, "")
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	if err := cmd.Flags().Set("output", outputJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(logOutput(cmd))
+	logger.Info("Installing collector")
+	printInstallReport(cmd.OutOrStdout(), logger, nil, &collector.InstallReport{CollectorID: 12, ExitCode: 0}, nil)
+	logger.Info("Install finished")
+
+	report := &collector.InstallReport{}
+	if err := json.Unmarshal(stdout.Bytes(), report); err != nil {
+		t.Fatalf("stdout is not valid json: %s\n%s", err, stdout.String())
+	}
+	if report.CollectorID != 12 {
+		t.Errorf("collector id = %d, want 12", report.CollectorID)
+	}
+	if stderr.Len() == 0 {
+		t.Error("logs not written on stderr")
+	}
+}
+
+func TestTextOutputLogsOnStdout(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	cmd := &cobra.Command{Use: "start"}
+	cmd.Flags().StringP("output", "o", outputText, "")
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+
+	logger := logrus.New()
+	logger.SetOutput(logOutput(cmd))
+	logger.Info("Installing collector")
+	if stdout.Len() == 0 || stderr.Len() != 0 {
+		t.Errorf("text output must log on stdout, stdout: %q, stderr: %q", stdout.String(), stderr.String())
+	}
+}
+
+func TestFailedRunDoesNotReportEarlierInstall(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "install-report.json")
+	if err := collector.WriteInstallReport(path, &collector.InstallReport{CollectorID: 1, InstalledVersion: "34001"}); err != nil {
+		t.Fatal(err)
+	}
+	defer func(old config.Config) {
+		*conf = old
+	}(*conf)
+	conf.InstallReportPath, conf.VersionSpec, conf.InstallUser = path, ">=35", "logicmonitor"
+
+	tests := []struct {
+		name      string
+		collector *models.Collector
+		err       error
+		want      collector.InstallReport
+	}{
+		{
+			name: "failed before collector was found",
+			err:  errors.New("collector group [prod] doesn't exist"),
+			want: collector.InstallReport{RequestedVersion: ">=35", InstallUser: "logicmonitor", Error: "collector group [prod] doesn't exist"},
+		},
+		{
+			name:      "failed before installer ran",
+			collector: &models.Collector{ID: 7},
+			err:       errors.New("no ga collector version satisfies >=35.000"),
+			want:      collector.InstallReport{CollectorID: 7, RequestedVersion: ">=35", InstallUser: "logicmonitor", Error: "no ga collector version satisfies >=35.000"},
+		},
+		{
+			name: "already installed",
+			want: collector.InstallReport{CollectorID: 1, InstalledVersion: "34001"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			printInstallReport(&stdout, logrus.New(), tt.collector, nil, tt.err)
+			got := collector.InstallReport{}
+			if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+				t.Fatalf("stdout is not valid json: %s", err)
+			}
+			got.StartedAt = tt.want.StartedAt
+			if got != tt.want {
+				t.Errorf("report = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/collector"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/jsonmask"
+)
+
+var upgradeMethod string
+
+// upgradeCmd represents the upgrade command
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade collector in place to another build",
+	Long: `Upgrades existing collector to --version, either by scheduling one time upgrade through
+collector upgrade api (--method api) or by running installer of new build over existing installation
+(--method installer), then waits until portal reports the new build. When new build isn't up within
+--wait-timeout, agent.conf taken before upgrade is restored, agent restarted and command exits with
+non-zero status.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := initialise(cmd); err != nil {
+			return err
+		}
+		conf.Configured = changedFlags(cmd)
+		err := conf.Validate()
+		if err != nil {
+			return fmt.Errorf("config validation failed with: %w", err)
+		}
+		if conf.VersionSpec == "" {
+			return fmt.Errorf("version must be set")
+		}
+		return mustLMClient()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := commandLogger(cmd)
+		maskedJsonStr, err := jsonmask.MaskJson(conf)
+
+		if err != nil {
+			logger.Warn("Couldn't mask sensitive data of configuration, cannot printing configuration on stdout")
+		} else {
+			logger.Debugf("Configuration: %s", maskedJsonStr)
+		}
+
+		c, err := collector.FindCollector(conf, lmClient)
+		if err != nil {
+			logger.Errorf("Finding collector failed with: %s", err)
+			os.Exit(1)
+		}
+		if err := collector.Upgrade(cmd.Context(), logger, creds, conf, lmClient, c, upgradeMethod); err != nil {
+			logger.Errorf("%s", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(upgradeCmd)
+
+	addStartFlags(upgradeCmd)
+	addWaitFlags(upgradeCmd)
+	upgradeCmd.Flags().StringVar(&upgradeMethod, "method", collector.UpgradeMethodAPI, "Upgrade through collector upgrade \"api\" or by running \"installer\" over existing installation")
+}
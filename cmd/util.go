@@ -31,3 +31,12 @@ func bindFlags(cmd *cobra.Command, v *viper.Viper) {
 		}
 	})
 }
+
+// changedFlags returns names of flags explicitly set by flag, env or config file, must be called after bindFlags
+func changedFlags(cmd *cobra.Command) map[string]bool {
+	changed := map[string]bool{}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		changed[f.Name] = true
+	})
+	return changed
+}
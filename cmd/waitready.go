@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/collector"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/jsonmask"
+)
+
+// waitReadyCmd represents the wait-ready command
+var waitReadyCmd = &cobra.Command{
+	Use:   "wait-ready",
+	Short: "Wait for collector to be up in portal",
+	Long: `Polls collector in portal until it is up (isDown false) and reports expected build, --build
+when set, otherwise --version. Exits with non-zero status and last seen heartbeat and status
+when collector isn't up within --wait-timeout.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := initialise(cmd); err != nil {
+			return err
+		}
+		conf.Configured = changedFlags(cmd)
+		err := conf.Validate()
+		if err != nil {
+			return fmt.Errorf("config validation failed with: %w", err)
+		}
+		return mustLMClient()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := commandLogger(cmd)
+		maskedJsonStr, err := jsonmask.MaskJson(conf)
+
+		if err != nil {
+			logger.Warn("Couldn't mask sensitive data of configuration, cannot printing configuration on stdout")
+		} else {
+			logger.Debugf("Configuration: %s", maskedJsonStr)
+		}
+
+		c, err := collector.FindCollector(conf, lmClient)
+		if err != nil {
+			logger.Errorf("Finding collector failed with: %s", err)
+			os.Exit(1)
+		}
+		build := conf.Build
+		if build == "" {
+			build = collector.ExpectedBuild(conf, nil)
+		}
+		if _, err := collector.WaitReady(cmd.Context(), logger, conf, lmClient, c.ID, build); err != nil {
+			logger.Errorf("%s", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(waitReadyCmd)
+
+	addStartFlags(waitReadyCmd)
+	addWaitFlags(waitReadyCmd)
+	waitReadyCmd.Flags().StringVar(&conf.Build, "build", "", "Build collector must report, defaults to --version (any build when neither is set)")
+}
+
+// addWaitFlags registers flags controlling how long to wait for collector to be up
+func addWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().DurationVar(&conf.WaitTimeout, "wait-timeout", 10*time.Minute, "Time to wait for collector to be up in portal")
+	cmd.Flags().DurationVar(&conf.WaitInterval, "wait-interval", 15*time.Second, "Interval between collector status checks")
+}
@@ -7,3 +7,18 @@ var VersionError = errors.New("version error")
 
 // CollectorGroupNotFoundError version error
 var CollectorGroupNotFoundError = errors.New("collector group error")
+
+// CollectorNotFoundError collector not found
+var CollectorNotFoundError = errors.New("collector not found")
+
+// AgentCrashedError agent process stayed dead beyond failure threshold
+var AgentCrashedError = errors.New("agent crashed")
+
+// WatchdogCrashedError watchdog process stayed dead beyond failure threshold
+var WatchdogCrashedError = errors.New("watchdog crashed")
+
+// CollectorNotReadyError collector didn't come up in portal within timeout
+var CollectorNotReadyError = errors.New("collector not ready")
+
+// InvalidInstallerError downloaded installer is not an executable collector installer
+var InvalidInstallerError = errors.New("invalid installer")
@@ -1,7 +1,6 @@
 package logicmonitor
 
 import (
-	"bytes"
 	"errors"
 	"io"
 	"net/http"
@@ -14,6 +13,7 @@ import (
 	"github.com/logicmonitor/lm-sdk-go/client/lm"
 	"github.com/sirupsen/logrus"
 	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/metrics"
 )
 
 func NewLMClient(c *config.Creds) (*client.LMSdkGo, error) {
@@ -41,6 +41,7 @@ func NewClient(c *client.Config) *client.LMSdkGo {
 
 	cli := new(client.LMSdkGo)
 	transport.Consumers["application/binary"] = LMBinaryFileConsumer()
+	transport.Transport = metrics.InstrumentRoundTripper(transport.Transport)
 	cli.Transport = transport
 
 	cli.LM = lm.New(transport, strfmt.Default, authInfo)
@@ -57,26 +58,14 @@ func LMBinaryFileConsumer() runtime.Consumer {
 		if reader == nil {
 			return errors.New("LMBinaryFileConsumer requires a reader") // early exit
 		}
-		buf := new(bytes.Buffer)
-		_, err := buf.ReadFrom(reader)
-		if err != nil {
-			return err
-		}
-		b := buf.Bytes()
 		w, ok := data.(io.Writer)
 		if !ok {
 			return errors.New("provided output object is not of type io.writer")
 			// the assertion failed.
 		}
-		_, err = w.Write(b)
-		if err != nil {
-			return err
-		}
-		c, ok := w.(interface{ Close() })
-		if ok {
-			c.Close()
-		}
-		return nil
+		// stream installer instead of buffering hundreds of MBs in memory
+		_, err := io.Copy(w, reader)
+		return err
 	})
 }
 
@@ -94,11 +83,12 @@ func newLMClientWithProxy(config *client.Config, argusConfig *config.Creds) (*cl
 	}
 	logrus.Infof("Using http/s proxy: %s with username: %s", argusConfig.ProxyUrl, argusConfig.ProxyUser)
 	httpClient := http.Client{
-		Transport: &http.Transport{ // nolint: exhaustivestruct
+		Transport: metrics.InstrumentRoundTripper(&http.Transport{ // nolint: exhaustivestruct
 			Proxy: http.ProxyURL(proxyURL),
-		},
+		}),
 	}
 	transport := httptransport.NewWithClient(config.TransportCfg.Host, config.TransportCfg.BasePath, config.TransportCfg.Schemes, &httpClient)
+	transport.Consumers["application/binary"] = LMBinaryFileConsumer()
 	authInfo := client.LMv1Auth(*config.AccessID, *config.AccessKey)
 	clientObj := new(client.LMSdkGo)
 	clientObj.Transport = transport
@@ -113,7 +103,9 @@ func newLMClientWithoutSSL(config *client.Config) (*client.LMSdkGo, error) {
 	if err != nil {
 		return nil, err
 	}
+	httpClient.Transport = metrics.InstrumentRoundTripper(httpClient.Transport)
 	transport := httptransport.NewWithClient(config.TransportCfg.Host, config.TransportCfg.BasePath, config.TransportCfg.Schemes, httpClient)
+	transport.Consumers["application/binary"] = LMBinaryFileConsumer()
 	authInfo := client.LMv1Auth(*config.AccessID, *config.AccessKey)
 	cli := new(client.LMSdkGo)
 	cli.Transport = transport
@@ -13,12 +13,17 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/vkumbhar94/lm-bootstrap-collector/pkg"
 	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/constants"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/metrics"
 	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/util"
 )
 
 func Apply(logger logrus.FieldLogger, cf *config.CollectorConf) error {
 	// return ApplyConf(logger, "agent.conf-test", pkg.Properties, cf)
-	return ApplyConf(logger, pkg.AgentConf, pkg.Properties, cf)
+	start := time.Now()
+	err := ApplyConf(logger, constants.AgentConfPath, pkg.Properties, cf)
+	metrics.ObservePhase(metrics.PhaseApplyConf, start, err)
+	return err
 }
 
 func ApplyConf(logger logrus.FieldLogger, confFile string, configFormat pkg.ConfigFormat, cf *config.CollectorConf) error {
@@ -0,0 +1,175 @@
+package collector
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/util"
+)
+
+const (
+	// checksumExt extension of file holding SHA-256 of cached installer
+	checksumExt = ".sha256"
+	// cachePrefix file name prefix of cached installers
+	cachePrefix = "logicmonitorsetup_"
+)
+
+// InstallerCache directory of verified installers keyed by account, collector, version, size, EA flag and arch,
+// evicting least recently used entries beyond size budget
+type InstallerCache struct {
+	logger  logrus.FieldLogger
+	dir     string
+	maxSize int64
+}
+
+// NewInstallerCache returns cache in dir, nil when dir is empty which disables caching
+func NewInstallerCache(logger logrus.FieldLogger, dir string, maxSize int64) *InstallerCache {
+	if dir == "" {
+		return nil
+	}
+	return &InstallerCache{logger: logger, dir: dir, maxSize: maxSize}
+}
+
+// InstallerCacheKey key of installer, e.g. acme_12_Linux64_34001_small_ga. Installers embed credentials of the
+// collector they were downloaded for, so account and collector id are part of the key.
+func InstallerCacheKey(account string, collectorID int32, osAndArch string, version int32, size string, ea bool) string {
+	channel := "ga"
+	if ea {
+		channel = "ea"
+	}
+	return fmt.Sprintf("%s_%d_%s_%d_%s_%s", account, collectorID, osAndArch, version, size, channel)
+}
+
+// path cached installer path, ext keeps extension of installer, e.g. .exe for windows
+func (c *InstallerCache) path(key string, ext string) string {
+	return filepath.Join(c.dir, cachePrefix+key+ext)
+}
+
+// Fetch copies cached installer to filename when cached copy matches its recorded SHA-256,
+// corrupted entries are removed. Reports whether installer was found.
+func (c *InstallerCache) Fetch(key string, filename string) bool {
+	if c == nil {
+		return false
+	}
+	cached := c.path(key, filepath.Ext(filename))
+	want, err := os.ReadFile(cached + checksumExt)
+	if err != nil {
+		return false
+	}
+	src, err := os.Open(cached)
+	if err != nil {
+		return false
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+	_, checksum, err := downloadFile(c.logger, filename, func(w io.Writer) error {
+		_, err := io.Copy(w, src)
+		return err
+	})
+	if err != nil {
+		c.logger.Warnf("Copying cached installer %s failed with: %s", cached, err)
+		return false
+	}
+	if checksum != strings.TrimSpace(string(want)) {
+		c.logger.Warnf("Cached installer %s is corrupted, removing it", cached)
+		c.remove(cached)
+		_ = os.Remove(filename)
+		return false
+	}
+	now := time.Now()
+	_ = os.Chtimes(cached, now, now)
+	c.logger.Infof("Using cached installer %s, sha256: %s", cached, checksum)
+	return true
+}
+
+// Store copies verified installer into cache and evicts old entries beyond size budget.
+// Installers embed collector credentials, so cache directory and files are only accessible to current user.
+func (c *InstallerCache) Store(key string, filename string, checksum string) {
+	if c == nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		c.logger.Warnf("Creating installer cache directory failed with: %s", err)
+		return
+	}
+	src, err := os.Open(filename)
+	if err != nil {
+		c.logger.Warnf("Caching installer failed with: %s", err)
+		return
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+	cached := c.path(key, filepath.Ext(filename))
+	_, _, err = downloadFile(c.logger, cached, func(w io.Writer) error {
+		_, err := io.Copy(w, src)
+		return err
+	})
+	if err == nil {
+		// cached copy is never executed
+		err = os.Chmod(cached, 0o600)
+	}
+	if err == nil {
+		err = os.WriteFile(cached+checksumExt, []byte(checksum+"\n"), 0o600)
+	}
+	if err != nil {
+		c.logger.Warnf("Caching installer failed with: %s", err)
+		c.remove(cached)
+		return
+	}
+	c.logger.Infof("Cached installer at %s", cached)
+	c.evict(cached)
+}
+
+// evict removes least recently used installers until cache fits size budget, keep is never evicted
+func (c *InstallerCache) evict(keep string) {
+	entries, err := filepath.Glob(filepath.Join(c.dir, cachePrefix+"*"))
+	if err != nil {
+		return
+	}
+	type entry struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []entry
+	var total int64
+	for _, p := range entries {
+		// skip checksums and partial copies
+		if strings.HasSuffix(p, checksumExt) || strings.HasSuffix(p, ".part") {
+			continue
+		}
+		stat, err := os.Stat(p)
+		if err != nil {
+			continue
+		}
+		files = append(files, entry{path: p, size: stat.Size(), modTime: stat.ModTime()})
+		total += stat.Size()
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+	for _, f := range files {
+		if total <= c.maxSize {
+			return
+		}
+		if f.path == keep {
+			continue
+		}
+		c.logger.Infof("Evicting cached installer %s (%s)", f.path, util.ToSI(f.size))
+		c.remove(f.path)
+		total -= f.size
+	}
+}
+
+func (c *InstallerCache) remove(cached string) {
+	_ = os.Remove(cached)
+	_ = os.Remove(cached + checksumExt)
+}
@@ -0,0 +1,154 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestInstallerCacheKey(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{
+			name: "collector id",
+			a:    InstallerCacheKey("acme", 1, "Linux64", 34001, "small", false),
+			b:    InstallerCacheKey("acme", 2, "Linux64", 34001, "small", false),
+		},
+		{
+			name: "account",
+			a:    InstallerCacheKey("acme", 1, "Linux64", 34001, "small", false),
+			b:    InstallerCacheKey("umbrella", 1, "Linux64", 34001, "small", false),
+		},
+		{
+			name: "channel",
+			a:    InstallerCacheKey("acme", 1, "Linux64", 34001, "small", false),
+			b:    InstallerCacheKey("acme", 1, "Linux64", 34001, "small", true),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.a == tt.b {
+				t.Errorf("keys must differ by %s, both are %s", tt.name, tt.a)
+			}
+		})
+	}
+}
+
+func TestInstallerCacheSeparatesCollectors(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewInstallerCache(logrus.New(), dir, 1<<30)
+	installer := filepath.Join(dir, "installer.bin")
+	if err := os.WriteFile(installer, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	_, checksum, err := copyInstaller(logrus.New(), installer, filepath.Join(dir, "copy.bin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.Store(InstallerCacheKey("acme", 1, "Linux64", 34001, "small", false), installer, checksum)
+
+	out := filepath.Join(dir, "out.bin")
+	if !cache.Fetch(InstallerCacheKey("acme", 1, "Linux64", 34001, "small", false), out) {
+		t.Error("installer cached for collector 1 not found")
+	}
+	if cache.Fetch(InstallerCacheKey("acme", 2, "Linux64", 34001, "small", false), out) {
+		t.Error("installer cached for collector 1 returned for collector 2")
+	}
+}
+
+// storeInstaller caches installer of given size with modification time age ago
+func storeInstaller(t *testing.T, cache *InstallerCache, dir string, version int32, size int, age time.Duration) string {
+	t.Helper()
+	installer := filepath.Join(dir, "installer.bin")
+	if err := os.WriteFile(installer, make([]byte, size), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	key := InstallerCacheKey("acme", 1, "Linux64", version, "small", false)
+	cache.Store(key, installer, "checksum")
+	cached := cache.path(key, ".bin")
+	modTime := time.Now().Add(-age)
+	if err := os.Chtimes(cached, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+	return cached
+}
+
+func TestInstallerCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewInstallerCache(logrus.New(), filepath.Join(dir, "cache"), 200)
+	oldest := storeInstaller(t, cache, dir, 34001, 100, 3*time.Hour)
+	older := storeInstaller(t, cache, dir, 34002, 100, 2*time.Hour)
+	// budget exceeded by newest, only oldest has to go to fit it
+	newest := storeInstaller(t, cache, dir, 34003, 50, 0)
+	if _, err := os.Stat(oldest); err == nil {
+		t.Error("oldest installer is not evicted")
+	}
+	if _, err := os.Stat(oldest + checksumExt); err == nil {
+		t.Error("checksum of oldest installer is not evicted")
+	}
+	for _, kept := range []string{older, newest} {
+		if _, err := os.Stat(kept); err != nil {
+			t.Errorf("installer %s is evicted: %s", kept, err)
+		}
+	}
+
+	// installer just stored is kept even when it alone exceeds budget
+	largest := storeInstaller(t, cache, dir, 34004, 300, 0)
+	if _, err := os.Stat(largest); err != nil {
+		t.Errorf("installer just stored is evicted: %s", err)
+	}
+	for _, evicted := range []string{older, newest} {
+		if _, err := os.Stat(evicted); err == nil {
+			t.Errorf("installer %s is not evicted", evicted)
+		}
+	}
+}
+
+func TestInstallerCachePermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix permissions")
+	}
+	dir := t.TempDir()
+	cache := NewInstallerCache(logrus.New(), filepath.Join(dir, "cache"), 1<<30)
+	cached := storeInstaller(t, cache, dir, 34001, 10, 0)
+	for path, want := range map[string]os.FileMode{
+		filepath.Join(dir, "cache"): os.ModeDir | 0o700,
+		cached:                      0o600,
+		cached + checksumExt:        0o600,
+	} {
+		stat, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stat.Mode() != want {
+			t.Errorf("%s mode = %s, want %s", path, stat.Mode(), want)
+		}
+	}
+}
+
+func TestInstallerCacheKeepsExtension(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewInstallerCache(logrus.New(), filepath.Join(dir, "cache"), 1<<30)
+	installer := filepath.Join(dir, "installer.exe")
+	if err := os.WriteFile(installer, []byte("MZ"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	_, checksum, err := copyInstaller(logrus.New(), installer, filepath.Join(dir, "copy.exe"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := InstallerCacheKey("acme", 1, "Win64", 34001, "small", false)
+	cache.Store(key, installer, checksum)
+	if _, err := os.Stat(filepath.Join(dir, "cache", "logicmonitorsetup_"+key+".exe")); err != nil {
+		t.Errorf("windows installer is not cached with .exe extension: %s", err)
+	}
+	if !cache.Fetch(key, filepath.Join(dir, "out.exe")) {
+		t.Error("cached windows installer not found")
+	}
+}
@@ -1,11 +1,13 @@
 package collector
 
 import (
-	"bufio"
-	"encoding/json"
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -17,25 +19,41 @@ import (
 	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/cerrors"
 	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
 	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/constants"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/metrics"
 	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/util"
 )
 
-func Start(logger logrus.FieldLogger, creds *config.Creds, conf *config.Config, client *client.LMSdkGo) error {
+func Start(ctx context.Context, logger logrus.FieldLogger, creds *config.Creds, conf *config.Config, client *client.LMSdkGo) (*models.Collector, *InstallReport, error) {
+	// refuse unsupported platform before registering collector
+	if err := conf.ResolvePlatform(); err != nil {
+		return nil, nil, err
+	}
+	if err := ResolveReferences(logger, conf, client); err != nil {
+		return nil, nil, err
+	}
+	findStart := time.Now()
 	collector, err := FindCollector(conf, client)
+	metrics.ObservePhase(metrics.PhaseFindCollector, findStart, err)
 	if err != nil {
 		logger.Warn("collector not found")
-		if conf.Kubernetes {
-			return fmt.Errorf("running in kubernetes but collector not found: %w", err)
+		if conf.Kubernetes && !conf.AutoRegister {
+			return nil, nil, fmt.Errorf("running in kubernetes but collector not found: %w", err)
 		}
 		// TODO: create collector from config
 		logger.Infof("Finding collector group: %s", conf.Group)
-		collectorGroupID, err := FindCollectorGroupID(conf.Group, client)
+		collectorGroupID, err := ResolveCollectorGroupID(conf, client)
+		if errors.Is(err, cerrors.CollectorGroupNotFoundError) && conf.CreateGroup && conf.GroupID == 0 {
+			logger.Infof("Collector group [%s] not found, creating it", conf.Group)
+			collectorGroupID, err = CreateCollectorGroup(logger, conf, client)
+		}
 		if err != nil {
-			return fmt.Errorf("collector group [%s] doesn't exist: %w", conf.Group, err)
+			return nil, nil, fmt.Errorf("collector group [%s] doesn't exist: %w", conf.Group, err)
 		}
+		newStart := time.Now()
 		collector, err = NewCollector(conf, client, collectorGroupID)
+		metrics.ObservePhase(metrics.PhaseNewCollector, newStart, err)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 	} else {
 		logger.Info("Collector Found")
@@ -47,6 +65,10 @@ func Start(logger logrus.FieldLogger, creds *config.Creds, conf *config.Config,
 		if _, err := os.Stat(constants.FirstRun); errors.Is(err, os.ErrNotExist) {
 			_ = util.Touch(constants.CollectorFound)
 		}
+		migrateInstanceID(ctx, logger, creds, conf, client, collector)
+		if _, err := Reconcile(ctx, logger, creds, conf, client, collector, false); err != nil {
+			logger.Warnf("Reconciling collector settings failed with: %s", err)
+		}
 	}
 
 	// let subsequent runs know that this isn't the first container run
@@ -54,27 +76,98 @@ func Start(logger logrus.FieldLogger, creds *config.Creds, conf *config.Config,
 	if _, err := os.Stat(constants.InstallPath + constants.AgentDirectory); !errors.Is(err, os.ErrNotExist) {
 		logger.Info(`Collector already installed.`)
 		_ = util.Cleanup(logger)
-		return nil
+		// installer only writes proxy on fresh install, keep agent.conf in line with current proxy
+		if creds.Proxy != nil {
+			if err := ApplyProxyConf(logger, creds); err != nil {
+				return collector, nil, fmt.Errorf("writing proxy settings into agent.conf failed with: %w", err)
+			}
+		}
+		ensureCollectorDevice(ctx, logger, conf, client, collector)
+		return collector, nil, nil
+	}
+	installStart := time.Now()
+	report, err := Install(logger, creds, conf, client, collector)
+	metrics.ObservePhase(metrics.PhaseInstall, installStart, err)
+	if err != nil {
+		return collector, report, err
+	}
+	ensureCollectorDevice(ctx, logger, conf, client, collector)
+	return collector, report, nil
+}
+
+// ensureCollectorDevice verifies collector device when device creation is enabled, failures don't stop collector
+func ensureCollectorDevice(ctx context.Context, logger logrus.FieldLogger, conf *config.Config, client *client.LMSdkGo, collector *models.Collector) {
+	if !conf.CreateDevice || conf.SkipInstall {
+		return
+	}
+	if err := EnsureCollectorDevice(ctx, logger, conf, client, collector.ID); err != nil {
+		logger.Warnf("Collector device verification failed with: %s", err)
 	}
-	return Install(logger, creds, conf, client, collector)
 }
 
-func Install(logger logrus.FieldLogger, creds *config.Creds, conf *config.Config, sdkGo *client.LMSdkGo, collector *models.Collector) error {
-	currentVersion := collector.Build
-	filename, err := DownloadInstaller(logger, conf, sdkGo, collector)
+func Install(logger logrus.FieldLogger, creds *config.Creds, conf *config.Config, sdkGo *client.LMSdkGo, collector *models.Collector) (*InstallReport, error) {
+	if err := ResolveVersion(logger, conf, sdkGo); err != nil {
+		return nil, err
+	}
+	downloadStart := time.Now()
+	filename, err := DownloadInstaller(logger, creds, conf, sdkGo, collector)
+	metrics.ObservePhase(metrics.PhaseDownloadInstaller, downloadStart, err)
+	if filename == "" && errors.Is(err, cerrors.VersionError) && conf.StrictVersion {
+		return nil, fmt.Errorf("requested collector version not available, strict version refuses falling back to latest: %w", err)
+	}
 	if filename == "" && errors.Is(err, cerrors.VersionError) {
 		collector.Build, conf.Version = "0", 0
 		logger.Warn("retry to get latest available collector version")
-		filename, err = DownloadInstaller(nil, conf, sdkGo, collector)
+		metrics.VersionFallbackTotal.Inc()
+		downloadStart = time.Now()
+		filename, err = DownloadInstaller(logger, creds, conf, sdkGo, collector)
+		metrics.ObservePhase(metrics.PhaseDownloadInstaller, downloadStart, err)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
+	if err != nil {
+		return nil, err
+	}
 	if conf.SkipInstall {
 		logger.Infof("Downloaded installer collector at [%s], skipping installation", filename)
 
-		return nil
+		return nil, nil
+	}
+	requested := ""
+	if v := requestedVersion(conf, collector); v != nil && *v != 0 {
+		requested = strconv.Itoa(int(*v))
+	}
+	report, err := runInstaller(logger, creds, conf, filename)
+	report.CollectorID = collector.ID
+	report.RequestedVersion = requested
+	if err == nil {
+		report.InstalledVersion = installedVersion(constants.InstallStatPath)
+		if report.InstalledVersion != "" {
+			if !buildMatches(report.InstalledVersion, requested) {
+				logger.Infof("Requested collector version %s is outdated so upgraded to %s version", requested, report.InstalledVersion)
+			}
+			collector.Build = report.InstalledVersion
+		}
+		if conf.StrictVersion && requested != "" && report.InstalledVersion != "" && !buildMatches(report.InstalledVersion, requested) {
+			err = fmt.Errorf("%w: installed collector version %s differs from requested %s", cerrors.VersionError, report.InstalledVersion, requested)
+		}
+	}
+	if err != nil {
+		report.Error = err.Error()
+	}
+	if conf.InstallReportPath != "" {
+		if werr := WriteInstallReport(conf.InstallReportPath, report); werr != nil {
+			logger.Warnf("Writing install report failed with: %s", werr)
+		} else {
+			logger.Infof("Install report written at %s", conf.InstallReportPath)
+		}
 	}
+	return report, err
+}
+
+// runInstaller runs downloaded installer, over existing installation as well
+func runInstaller(logger logrus.FieldLogger, creds *config.Creds, conf *config.Config, filename string) (*InstallReport, error) {
 	logger.Infof("Installing collector: %s", filename)
 	var installArgs []string
 	// Running whole script as non-root
@@ -82,73 +175,38 @@ func Install(logger logrus.FieldLogger, creds *config.Creds, conf *config.Config
 	//	installArgs = append(installArgs, "echo", creds.SudoPass, "|", "sudo", "-S")
 	//}
 	installArgs = append(installArgs, filename, "-y")
-	//  force update the collector object to ensure all details are up-to-date
-	//  e.g. build version
-	if collector.Build != "0" {
-		params := lm.NewGetCollectorByIDParams()
-		params.ID = collector.ID
-		c, err := sdkGo.LM.GetCollectorByID(params)
-		if err == nil {
-			logger.Infof("Collector version: %s", c.Payload.Build)
-			currentVersion = c.Payload.Build
-		}
-	}
 	//if conf.Version >= constants.MinNonRootInstallVer || conf.UseEa || conf.Version == 0 {
 	//	installArgs = append(installArgs, "-u", "root")
 	//}
 	installArgs = append(installArgs, "-u", conf.InstallUser)
-	if creds.ProxyUrl != "" {
-		installArgs = append(installArgs, "-p", creds.Proxy.Host)
-		if creds.ProxyUser != "" {
-			installArgs = append(installArgs, "-U", creds.ProxyUser)
-			if creds.ProxyPass != "" {
-				installArgs = append(installArgs, "-P", creds.ProxyPass)
-			}
-		}
+	if constants.InstallPath != constants.DefaultInstallPath {
+		installArgs = append(installArgs, "-d", strings.TrimSuffix(constants.InstallPath, "/"))
 	}
+	installArgs = append(installArgs, proxyInstallArgs(creds)...)
 	logger.Debugf("Running command: %v", installArgs)
-	err, stdout, stderr := util.Shellout(installArgs[0], installArgs[1:]...)
+	report := &InstallReport{InstallUser: conf.InstallUser, Installer: filename, StartedAt: time.Now()}
+	err, stdout, stderr := util.ShelloutEnv(proxyEnv(creds), installArgs[0], installArgs[1:]...)
+	report.DurationSeconds = time.Since(report.StartedAt).Seconds()
+	report.ExitCode = exitCode(err)
+	report.StdoutTail, report.StderrTail = tail(stdout, reportTailLines), tail(stderr, reportTailLines)
 	logger.Debugf("Install err: %s, stdout: %s, stderr: %s", err, stdout, stderr)
 	if err != nil && !strings.Contains(stdout, "LogicMonitor Collector has been installed successfully") {
-		return err
+		return report, err
 	}
 	if creds.IgnoreSSL {
 		_, _, _ = util.Shellout("sed", "-i",
 			"s/EnforceLogicMonitorSSL=true/EnforceLogicMonitorSSL=false/g",
-			"/usr/local/logicmonitor/agent/conf/agent.conf")
-	}
-	logger.Info("Cleaning up downloaded installer")
-	// log message if version is outdated
-	if f, err := os.Open(constants.InstallStatPath); err == nil {
-		defer func(f *os.File) {
-			_ = f.Close()
-		}(f)
-		scanner := bufio.NewScanner(f)
-
-		for scanner.Scan() {
-			line := scanner.Text()
-			if strings.Contains(line, "complexInfo=") {
-				val := strings.Split(line, "=")[1]
-				complexInfo := map[string]any{}
-				_ = json.Unmarshal([]byte(val), &complexInfo)
-				if installedCollector, ok := complexInfo["installedCollector"]; ok {
-					if cm, ok := installedCollector.(map[string]any); ok {
-						if version, ok := cm["version"]; ok {
-							installedVersion := version.(string)
-							upgradeMsg := fmt.Sprintf("Requested installedCollector version %s is outdated so upgraded to %s  version ",
-								currentVersion, installedVersion)
-							logger.Info(upgradeMsg)
-						}
-					}
-				}
-
-			}
+			constants.AgentConfPath)
+	}
+	if creds.Proxy != nil {
+		if err := ApplyProxyConf(logger, creds); err != nil {
+			return report, fmt.Errorf("writing proxy settings into agent.conf failed with: %w", err)
 		}
 	}
-	return nil
+	return report, nil
 }
 
-func DownloadInstaller(logger logrus.FieldLogger, conf *config.Config, sdkGo *client.LMSdkGo, collector *models.Collector) (string, error) {
+func DownloadInstaller(logger logrus.FieldLogger, creds *config.Creds, conf *config.Config, sdkGo *client.LMSdkGo, collector *models.Collector) (string, error) {
 	logger.Infof("Downloading collector %d", collector.ID)
 
 	params := lm.NewGetCollectorInstallerParamsWithTimeout(10 * time.Minute)
@@ -160,26 +218,43 @@ func DownloadInstaller(logger logrus.FieldLogger, conf *config.Config, sdkGo *cl
 	params.SetCollectorID(collector.ID)
 	params.SetUseEA(&conf.UseEa)
 
-	osAndArch := constants.DefaultOs + "64"
-	if strconv.IntSize == 32 {
-		osAndArch = constants.DefaultOs + "32"
+	if err := conf.ResolvePlatform(); err != nil {
+		return "", err
 	}
+	osAndArch := conf.Platform.OsAndArch
 	params.SetOsAndArch(osAndArch)
 
-	if conf.Version != 0 {
-		params.SetCollectorVersion(&conf.Version)
-	} else if collector.Build != "0" && !conf.UseEa {
-		v, _ := strconv.ParseInt(collector.Build, 10, 32)
-		v2 := int32(v)
-		params.SetCollectorVersion(&v2)
+	version := requestedVersion(conf, collector)
+	params.SetCollectorVersion(version)
+	filename := conf.InstallerFileName()
+
+	requested := ""
+	cache := NewInstallerCache(logger, conf.InstallerCacheDir, conf.InstallerCacheSize)
+	if conf.InstallerPath != "" {
+		// installer is already local
+		cache = nil
+	}
+	if version != nil && *version != 0 {
+		requested = strconv.Itoa(int(*version))
+		if cache.Fetch(InstallerCacheKey(creds.Account, collector.ID, osAndArch, *version, csize, conf.UseEa), filename) {
+			return filename, nil
+		}
 	}
-	filename := fmt.Sprintf("%slogicmonitorsetupx64_%d.bin", constants.TempPath, conf.ID)
 
-	f, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE, 0o755)
-	if err != nil {
-		return "", err
+	var size int64
+	var checksum string
+	var err error
+	switch {
+	case conf.InstallerPath != "":
+		size, checksum, err = copyInstaller(logger, conf.InstallerPath, filename)
+	case conf.InstallerURL != "":
+		size, checksum, err = fetchInstaller(logger, conf, filename)
+	default:
+		size, checksum, err = downloadFile(logger, filename, func(w io.Writer) error {
+			_, err := sdkGo.LM.GetCollectorInstaller(params, w)
+			return err
+		})
 	}
-	_, err = sdkGo.LM.GetCollectorInstaller(params, f)
 	if err != nil {
 		msg := err.Error()
 		if strings.Contains(msg, "only those versions") {
@@ -188,29 +263,76 @@ func DownloadInstaller(logger logrus.FieldLogger, conf *config.Config, sdkGo *cl
 		}
 		return "", err
 	}
-	defer func() {
-		_ = f.Close()
-	}()
-	logger.Infof("Downloaded installer at %s", filename)
-	// detect cases where we download an invalid installer
-	stat, err := os.Stat(filename)
-	if err == nil {
-		logger.Infof("Installer size: %s", util.ToSI(stat.Size()))
-	}
+	logger.Infof("Installer placed at %s", filename)
+	logger.Infof("Installer size: %s, sha256: %s", util.ToSI(size), checksum)
+	metrics.InstallerSize.Set(float64(size))
 
+	installerVersion, err := ValidateInstaller(conf.Platform, filename, requested, conf.StrictVersion)
+	if err != nil {
+		return "", err
+	}
+	if installerVersion != "" {
+		logger.Infof("Installer version: %s", installerVersion)
+		if !buildMatches(installerVersion, requested) {
+			logger.Warnf("Installer version %s found in installer header doesn't match requested version %s, installing anyway", installerVersion, requested)
+		}
+	} else if requested != "" {
+		logger.Warnf("Installer version not found in installer header, skipping check against requested version %s", requested)
+	}
+	if requested == "" {
+		requested = normalizeBuild(installerVersion)
+	}
+	if v, err := strconv.ParseInt(requested, 10, 32); err == nil && v != 0 {
+		cache.Store(InstallerCacheKey(creds.Account, collector.ID, osAndArch, int32(v), csize, conf.UseEa), filename, checksum)
+	}
 	return filename, nil
 }
 
+// requestedVersion collector version requested from installer api, nil for latest
+func requestedVersion(conf *config.Config, collector *models.Collector) *int32 {
+	if conf.Version != 0 {
+		return &conf.Version
+	} else if conf.InstallerPath != "" || conf.InstallerURL != "" {
+		// mirrored installer only has to match explicitly requested version, not current collector build
+		return nil
+	} else if collector.Build != "0" && !conf.UseEa {
+		v, _ := strconv.ParseInt(collector.Build, 10, 32)
+		v2 := int32(v)
+		return &v2
+	}
+	return nil
+}
+
 func NewCollector(conf *config.Config, sdkGo *client.LMSdkGo, collectorGroupID int32) (*models.Collector, error) {
+	deviceGroupID := int32(0)
+	if conf.CreateDevice {
+		var err error
+		deviceGroupID, err = ResolveDeviceGroupID(conf, sdkGo)
+		if err != nil {
+			return nil, err
+		}
+	}
 	collector := &models.Collector{
-		CollectorGroupID:              collectorGroupID,
-		BackupAgentID:                 conf.BackupCollectorID,
-		EnableFailBack:                conf.EnableFailBack,
-		EscalatingChainID:             conf.EscalatingChainID,
-		ID:                            conf.ID,
-		ResendIval:                    conf.ResendInterval,
-		SuppressAlertClear:            conf.SuppressAlertClear,
-		NeedAutoCreateCollectorDevice: false,
+		CollectorGroupID:                collectorGroupID,
+		BackupAgentID:                   conf.BackupCollectorID,
+		EnableFailBack:                  conf.EnableFailBack,
+		EscalatingChainID:               conf.EscalatingChainID,
+		ID:                              conf.ID,
+		ResendIval:                      conf.ResendInterval,
+		SuppressAlertClear:              conf.SuppressAlertClear,
+		NeedAutoCreateCollectorDevice:   conf.CreateDevice,
+		SpecifiedCollectorDeviceGroupID: deviceGroupID,
+	}
+	props := map[string]string{}
+	for k, v := range conf.Properties {
+		props[k] = v
+	}
+	if conf.InstanceID != "" {
+		// stamp collector with instance id so that it is found again even if description changes
+		props[conf.InstanceIDProperty] = conf.InstanceID
+	}
+	if len(props) > 0 {
+		collector.CustomProperties = toNameAndValues(props)
 	}
 
 	if conf.Description != "" {
@@ -243,21 +365,86 @@ func FindCollectorGroupID(collectorGroup string, sdkGo *client.LMSdkGo) (int32,
 	}
 	// trim leading / if it exists
 	collectorGroup = strings.TrimSuffix(collectorGroup, "/")
-	params := lm.NewGetCollectorGroupListParams()
-	size := int32(-1)
-	params.SetSize(&size)
-	resp, err := sdkGo.LM.GetCollectorGroupList(params)
+	groups, err := ListCollectorGroups(sdkGo, FilterEquals("name", collectorGroup))
 	if err != nil {
 		return -1, err
 	}
-	for _, c := range resp.Payload.Items {
-		if *c.Name == collectorGroup {
+	for _, c := range groups {
+		if c.Name != nil && *c.Name == collectorGroup {
 			return c.ID, nil
 		}
 	}
 	return -1, cerrors.CollectorGroupNotFoundError
 }
 
+// ResolveCollectorGroupID returns configured collector group ID when set and exists, otherwise finds group by name
+func ResolveCollectorGroupID(conf *config.Config, sdkGo *client.LMSdkGo) (int32, error) {
+	if conf.GroupID == 0 {
+		return FindCollectorGroupID(conf.Group, sdkGo)
+	}
+	params := lm.NewGetCollectorGroupByIDParams()
+	params.SetID(conf.GroupID)
+	resp, err := sdkGo.LM.GetCollectorGroupByID(params)
+	if err != nil {
+		if GetHTTPStatusCodeFromLMSDKError(err) == http.StatusNotFound {
+			return -1, fmt.Errorf("collector group id %d: %w", conf.GroupID, cerrors.CollectorGroupNotFoundError)
+		}
+		return -1, err
+	}
+	return resp.Payload.ID, nil
+}
+
+// CreateCollectorGroup creates collector group with description and custom properties from config.
+// Collector groups are flat in Logicmonitor, so nested path like prod/eu/k8s is kept as group name,
+// the same name FindCollectorGroupID matches on.
+// When other replica creates the same group concurrently, ID of that group is returned.
+func CreateCollectorGroup(logger logrus.FieldLogger, conf *config.Config, sdkGo *client.LMSdkGo) (int32, error) {
+	name := strings.TrimSuffix(conf.Group, "/")
+	group := &models.CollectorGroup{
+		Name:             &name,
+		Description:      conf.GroupDescription,
+		CustomProperties: toNameAndValues(conf.GroupProperties),
+	}
+	params := lm.NewAddCollectorGroupParams()
+	params.SetBody(group)
+	resp, err := sdkGo.LM.AddCollectorGroup(params)
+	if err != nil {
+		if isAlreadyExists(err) {
+			logger.Infof("Collector group [%s] already exists, seems created concurrently", name)
+			return FindCollectorGroupID(conf.Group, sdkGo)
+		}
+		return -1, err
+	}
+	logger.Infof("Created collector group [%s] with id %d", name, resp.Payload.ID)
+	return resp.Payload.ID, nil
+}
+
+func isAlreadyExists(err error) bool {
+	// Status 600: The record already exists
+	code := GetHTTPStatusCodeFromLMSDKError(err)
+	if code == 600 || code == http.StatusConflict {
+		return true
+	}
+	if es, ok := err.(*lm.AddCollectorGroupDefault); ok && es.Payload != nil && es.Payload.ErrorCode == 600 {
+		return true
+	}
+	return strings.Contains(strings.ToLower(err.Error()), "already exist")
+}
+
+func toNameAndValues(props map[string]string) []*models.NameAndValue {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var nvs []*models.NameAndValue
+	for _, k := range keys {
+		name, value := k, props[k]
+		nvs = append(nvs, &models.NameAndValue{Name: &name, Value: &value})
+	}
+	return nvs
+}
+
 func FindCollector(conf *config.Config, sdkGo *client.LMSdkGo) (*models.Collector, error) {
 	if conf.ID != 0 {
 		params := lm.NewGetCollectorByIDParams()
@@ -268,12 +455,25 @@ func FindCollector(conf *config.Config, sdkGo *client.LMSdkGo) (*models.Collecto
 		}
 		return resp.Payload, nil
 	} else {
-		list, err := sdkGo.LM.GetCollectorList(nil)
+		if conf.InstanceID != "" {
+			c, err := FindCollectorByProperty(sdkGo, conf.InstanceIDProperty, conf.InstanceID)
+			if err == nil {
+				return c, nil
+			}
+			if !errors.Is(err, cerrors.CollectorNotFoundError) || conf.Description == "" {
+				return nil, err
+			}
+		}
+		list, err := ListCollectors(sdkGo, FilterEquals("description", conf.Description))
 		if err != nil {
 			return nil, err
 		}
-		for _, c := range list.Payload.Items {
+		for _, c := range list {
 			if c.Description == conf.Description {
+				if id, ok := propertyValue(c.CustomProperties, conf.InstanceIDProperty); ok && conf.InstanceID != "" && id != conf.InstanceID {
+					// collector with same description belongs to another instance
+					continue
+				}
 				params := lm.NewGetCollectorByIDParams()
 				params.SetID(c.ID)
 				resp, err := sdkGo.LM.GetCollectorByID(params)
@@ -281,10 +481,63 @@ func FindCollector(conf *config.Config, sdkGo *client.LMSdkGo) (*models.Collecto
 					return nil, err
 				}
 				return resp.Payload, nil
-				// return c, nil
+			}
+		}
+	}
+	return nil, cerrors.CollectorNotFoundError
+}
 
+// propertyValue value of custom property, reports whether property is present
+func propertyValue(props []*models.NameAndValue, name string) (string, bool) {
+	for _, p := range props {
+		if p.Name != nil && *p.Name == name {
+			if p.Value == nil {
+				return "", true
+			}
+			return *p.Value, true
+		}
+	}
+	return "", false
+}
+
+// migrateInstanceID stamps instance id on collector found by description, so that it is found by instance id
+// from now on even if its description is edited in portal
+func migrateInstanceID(ctx context.Context, logger logrus.FieldLogger, creds *config.Creds, conf *config.Config, sdkGo *client.LMSdkGo, collector *models.Collector) {
+	if conf.InstanceID == "" {
+		return
+	}
+	if _, ok := propertyValue(collector.CustomProperties, conf.InstanceIDProperty); ok {
+		return
+	}
+	name, value := conf.InstanceIDProperty, conf.InstanceID
+	props := append(collector.CustomProperties, &models.NameAndValue{Name: &name, Value: &value})
+	// custom properties are replaced as a whole, send existing ones along
+	if err := patchCollector(ctx, creds, sdkGo, collector.ID, map[string]any{"customProperties": props}); err != nil {
+		logger.Warnf("Stamping %s=%s on collector %d failed with: %s, it is still found by description", name, value, collector.ID, err)
+		return
+	}
+	collector.CustomProperties = props
+	logger.Infof("Collector %d found by description migrated to instance id: %s=%s", collector.ID, name, value)
+}
+
+// FindCollectorByProperty finds collector having custom property with given value
+func FindCollectorByProperty(sdkGo *client.LMSdkGo, name string, value string) (*models.Collector, error) {
+	list, err := ListCollectors(sdkGo, FilterEquals("customProperties.value", value))
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range list {
+		for _, p := range c.CustomProperties {
+			if p.Name != nil && p.Value != nil && *p.Name == name && *p.Value == value {
+				params := lm.NewGetCollectorByIDParams()
+				params.SetID(c.ID)
+				resp, err := sdkGo.LM.GetCollectorByID(params)
+				if err != nil {
+					return nil, err
+				}
+				return resp.Payload, nil
 			}
 		}
 	}
-	return nil, errors.New("collector not found")
+	return nil, fmt.Errorf("collector with %s=%s: %w", name, value, cerrors.CollectorNotFoundError)
 }
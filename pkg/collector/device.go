@@ -0,0 +1,182 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/logicmonitor/lm-sdk-go/client"
+	"github.com/logicmonitor/lm-sdk-go/client/lm"
+	"github.com/logicmonitor/lm-sdk-go/models"
+	"github.com/sirupsen/logrus"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+)
+
+// SystemCategoriesProperty device property holding categories
+const SystemCategoriesProperty = "system.categories"
+
+// deviceDisplayNameData data available to device display name template
+type deviceDisplayNameData struct {
+	config.TemplateData
+	CollectorID int32
+	Description string
+}
+
+// FindDeviceGroupID finds device group by full path, e.g. Collectors/prod
+func FindDeviceGroupID(sdkGo *client.LMSdkGo, fullPath string) (int32, error) {
+	fullPath = strings.Trim(fullPath, "/")
+	// root group
+	if fullPath == "" {
+		return 1, nil
+	}
+	filter := FilterEquals("fullPath", fullPath)
+	size := pageSize
+	for offset := int32(0); ; offset += size {
+		params := lm.NewGetDeviceGroupListParams()
+		params.SetFilter(&filter)
+		params.SetSize(&size)
+		off := offset
+		params.SetOffset(&off)
+		resp, err := sdkGo.LM.GetDeviceGroupList(params)
+		if err != nil {
+			return -1, err
+		}
+		for _, g := range resp.Payload.Items {
+			if g.FullPath == fullPath {
+				return g.ID, nil
+			}
+		}
+		if int32(len(resp.Payload.Items)) < size {
+			return -1, fmt.Errorf("device group [%s] not found", fullPath)
+		}
+	}
+}
+
+// ResolveDeviceGroupID returns device group the collector device should land in, 0 when not configured
+func ResolveDeviceGroupID(conf *config.Config, sdkGo *client.LMSdkGo) (int32, error) {
+	if conf.DeviceGroupID != 0 {
+		return conf.DeviceGroupID, nil
+	}
+	if conf.DeviceGroup == "" {
+		return 0, nil
+	}
+	return FindDeviceGroupID(sdkGo, conf.DeviceGroup)
+}
+
+// deviceWaitInterval interval of polling collector until its device gets created
+const deviceWaitInterval = 10 * time.Second
+
+// EnsureCollectorDevice waits for collector device to be created and makes sure it is in configured
+// device group with configured display name, categories and custom properties. Returns early when
+// collector doesn't auto create its device.
+func EnsureCollectorDevice(ctx context.Context, logger logrus.FieldLogger, conf *config.Config, sdkGo *client.LMSdkGo, collectorID int32) error {
+	groupID, err := ResolveDeviceGroupID(conf, sdkGo)
+	if err != nil {
+		return err
+	}
+
+	var collector *models.Collector
+	deadline := time.Now().Add(conf.DeviceWaitTimeout)
+	ticker := time.NewTicker(deviceWaitInterval)
+	defer ticker.Stop()
+	for {
+		params := lm.NewGetCollectorByIDParams()
+		params.SetContext(ctx)
+		params.SetID(collectorID)
+		resp, err := sdkGo.LM.GetCollectorByID(params)
+		if err != nil {
+			return err
+		}
+		collector = resp.Payload
+		if collector.CollectorDeviceID != 0 {
+			break
+		}
+		if !toBool(collector.NeedAutoCreateCollectorDevice) {
+			logger.Infof("Collector %d doesn't auto create its device, skipping collector device verification", collectorID)
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("collector device of collector %d not created within %s", collectorID, conf.DeviceWaitTimeout)
+		}
+		logger.Info("Waiting for collector device to be created")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+
+	params := lm.NewGetDeviceByIDParams()
+	params.SetID(collector.CollectorDeviceID)
+	resp, err := sdkGo.LM.GetDeviceByID(params)
+	if err != nil {
+		return fmt.Errorf("getting collector device %d failed with: %w", collector.CollectorDeviceID, err)
+	}
+	device := resp.Payload
+	logger.Infof("Collector device %d found", device.ID)
+
+	patch := &models.Device{Name: device.Name, DisplayName: device.DisplayName}
+	var patchFields []string
+	if groupID != 0 && !containsGroup(device.HostGroupIds, groupID) {
+		logger.Warnf("Collector device %d is in device groups [%s], moving it to device group %d", device.ID, device.HostGroupIds, groupID)
+		patch.HostGroupIds = strconv.Itoa(int(groupID))
+		patchFields = append(patchFields, "hostGroupIds")
+	}
+	if conf.DeviceDisplayName != "" {
+		displayName, err := renderDisplayName(conf, collector)
+		if err != nil {
+			return err
+		}
+		if device.DisplayName == nil || *device.DisplayName != displayName {
+			patch.DisplayName = &displayName
+			patchFields = append(patchFields, "displayName")
+		}
+	}
+	props := map[string]string{}
+	for k, v := range conf.DeviceProperties {
+		props[k] = v
+	}
+	if len(conf.DeviceCategories) > 0 {
+		props[SystemCategoriesProperty] = strings.Join(conf.DeviceCategories, ",")
+	}
+	if len(props) > 0 {
+		patch.CustomProperties = toNameAndValues(props)
+		patchFields = append(patchFields, "customProperties")
+	}
+	if len(patchFields) == 0 {
+		return nil
+	}
+
+	patchParams := lm.NewPatchDeviceParams()
+	patchParams.SetID(device.ID)
+	patchParams.SetBody(patch)
+	fields := strings.Join(patchFields, ",")
+	patchParams.SetPatchFields(&fields)
+	// replace only given custom properties, default "refresh" would drop others
+	opType := "replace"
+	patchParams.SetOpType(&opType)
+	if _, err := sdkGo.LM.PatchDevice(patchParams); err != nil {
+		return fmt.Errorf("updating collector device %d failed with: %w", device.ID, err)
+	}
+	logger.Infof("Collector device %d updated: %s", device.ID, fields)
+	return nil
+}
+
+func containsGroup(hostGroupIds string, groupID int32) bool {
+	for _, id := range strings.Split(hostGroupIds, ",") {
+		if strings.TrimSpace(id) == strconv.Itoa(int(groupID)) {
+			return true
+		}
+	}
+	return false
+}
+
+func renderDisplayName(conf *config.Config, collector *models.Collector) (string, error) {
+	return config.Render("device display name", conf.DeviceDisplayName, deviceDisplayNameData{
+		TemplateData: conf.TemplateData(),
+		CollectorID:  collector.ID,
+		Description:  collector.Description,
+	})
+}
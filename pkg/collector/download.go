@@ -0,0 +1,147 @@
+package collector
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/metrics"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/util"
+)
+
+// progressInterval interval between download progress logs
+const progressInterval = 5 * time.Second
+
+// progressWriter counts written bytes and periodically logs download progress
+type progressWriter struct {
+	logger  logrus.FieldLogger
+	written int64
+	start   time.Time
+	logged  time.Time
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	p.written += int64(len(b))
+	if now := time.Now(); now.Sub(p.logged) >= progressInterval {
+		p.logged = now
+		p.logger.Infof("Downloaded %s (%s/s)", util.ToSI(p.written), util.ToSI(p.rate(now)))
+	}
+	return len(b), nil
+}
+
+func (p *progressWriter) rate(now time.Time) int64 {
+	elapsed := now.Sub(p.start).Seconds()
+	if elapsed < 1 {
+		return p.written
+	}
+	return int64(float64(p.written) / elapsed)
+}
+
+// downloadFile streams download into temp file next to filename while computing its SHA-256, then fsyncs
+// and atomically renames it into place, so that filename never holds partial or stale content.
+// Returns written size and hex encoded SHA-256.
+func downloadFile(logger logrus.FieldLogger, filename string, download func(w io.Writer) error) (int64, string, error) {
+	f, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*.part")
+	if err != nil {
+		return 0, "", err
+	}
+	tmp := f.Name()
+	done := false
+	defer func() {
+		if !done {
+			_ = f.Close()
+			_ = os.Remove(tmp)
+		}
+	}()
+
+	hash := sha256.New()
+	now := time.Now()
+	progress := &progressWriter{logger: logger, start: now, logged: now}
+	if err := download(io.MultiWriter(f, hash, progress)); err != nil {
+		return 0, "", err
+	}
+	if err := f.Sync(); err != nil {
+		return 0, "", fmt.Errorf("syncing %s failed with: %w", tmp, err)
+	}
+	if err := f.Chmod(0o755); err != nil {
+		return 0, "", err
+	}
+	if err := f.Close(); err != nil {
+		return 0, "", err
+	}
+	if err := os.Rename(tmp, filename); err != nil {
+		return 0, "", err
+	}
+	done = true
+	if progress.written > 0 {
+		logger.Infof("Downloaded %s in %s", util.ToSI(progress.written), time.Since(progress.start).Round(time.Second))
+	}
+	return progress.written, hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// copyInstaller copies local installer to filename
+func copyInstaller(logger logrus.FieldLogger, path string, filename string) (int64, string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return 0, "", err
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+	logger.Infof("Copying installer from %s", path)
+	return downloadFile(logger, filename, func(w io.Writer) error {
+		_, err := io.Copy(w, src)
+		return err
+	})
+}
+
+// fetchInstaller downloads installer from mirror url, with basic auth and custom CA bundle when configured
+func fetchInstaller(logger logrus.FieldLogger, conf *config.Config, filename string) (int64, string, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if conf.InstallerCABundle != "" {
+		pem, err := os.ReadFile(conf.InstallerCABundle)
+		if err != nil {
+			return 0, "", fmt.Errorf("reading installer CA bundle failed with: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return 0, "", fmt.Errorf("no certificates found in installer CA bundle %s", conf.InstallerCABundle)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	httpClient := &http.Client{Transport: metrics.InstrumentRoundTripper(transport), Timeout: 10 * time.Minute}
+
+	req, err := http.NewRequest(http.MethodGet, conf.InstallerURL, nil)
+	if err != nil {
+		return 0, "", err
+	}
+	if conf.InstallerUser != "" {
+		req.SetBasicAuth(conf.InstallerUser, conf.InstallerPass)
+	}
+	logger.Infof("Downloading installer from %s", req.URL.Redacted())
+	return downloadFile(logger, filename, func(w io.Writer) error {
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+			return fmt.Errorf("downloading installer from %s failed with status %s: %s", req.URL.Redacted(), resp.Status, strings.TrimSpace(string(body)))
+		}
+		_, err = io.Copy(w, resp.Body)
+		return err
+	})
+}
@@ -0,0 +1,74 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/logicmonitor/lm-sdk-go/models"
+	"github.com/sirupsen/logrus"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/cerrors"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+)
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestFindCollectorDescriptionFallback(t *testing.T) {
+	tests := []struct {
+		name      string
+		props     []*models.NameAndValue
+		wantFound bool
+		wantPatch bool
+	}{
+		{
+			name:      "legacy collector migrated to instance id",
+			props:     []*models.NameAndValue{{Name: stringPtr("team"), Value: stringPtr("infra")}},
+			wantFound: true,
+			wantPatch: true,
+		},
+		{
+			name:  "collector of other instance refused",
+			props: []*models.NameAndValue{{Name: stringPtr(config.DefaultInstanceIDProperty), Value: stringPtr("other-instance")}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			portal, sdkGo, creds := newFakePortal(t, &models.Collector{ID: 7, Description: "prod-collector", CustomProperties: tt.props})
+			conf := &config.Config{Description: "prod-collector", InstanceID: "this-instance", InstanceIDProperty: config.DefaultInstanceIDProperty}
+
+			c, err := FindCollector(conf, sdkGo)
+			if !tt.wantFound {
+				if !errors.Is(err, cerrors.CollectorNotFoundError) {
+					t.Fatalf("FindCollector() error = %v, want %v", err, cerrors.CollectorNotFoundError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FindCollector() failed with: %s", err)
+			}
+			logger := logrus.New()
+			logger.SetOutput(io.Discard)
+			migrateInstanceID(context.Background(), logger, creds, conf, sdkGo, c)
+
+			patch := portal.patch(7)
+			if (patch != nil) != tt.wantPatch {
+				t.Fatalf("patch = %v, want patch %v", patch, tt.wantPatch)
+			}
+			props, _ := patch["customProperties"].([]any)
+			got := map[string]any{}
+			for _, p := range props {
+				kv := p.(map[string]any)
+				got[kv["name"].(string)] = kv["value"]
+			}
+			if got[config.DefaultInstanceIDProperty] != "this-instance" || got["team"] != "infra" {
+				t.Errorf("patched custom properties = %v, want existing ones and instance id", got)
+			}
+			if v, _ := propertyValue(c.CustomProperties, config.DefaultInstanceIDProperty); v != "this-instance" {
+				t.Errorf("collector instance id = %q, want this-instance", v)
+			}
+		})
+	}
+}
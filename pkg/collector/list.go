@@ -0,0 +1,115 @@
+package collector
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/logicmonitor/lm-sdk-go/client"
+	"github.com/logicmonitor/lm-sdk-go/client/lm"
+	"github.com/logicmonitor/lm-sdk-go/models"
+)
+
+// pageSize max page size supported by Logicmonitor list APIs
+const pageSize = int32(1000)
+
+// FilterEquals builds Logicmonitor API filter matching field value exactly, e.g. description:"my collector"
+func FilterEquals(field, value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return fmt.Sprintf(`%s:"%s"`, field, value)
+}
+
+// lastPage reports whether page of fetched items was the last one
+func lastPage(pageItems int, fetched int, total int32) bool {
+	// total is negative when Logicmonitor doesn't count matching records
+	return int32(pageItems) < pageSize || (total > 0 && int32(fetched) >= total)
+}
+
+// ListCollectors returns all collectors matching filter, paging through results
+func ListCollectors(sdkGo *client.LMSdkGo, filter string) ([]*models.CollectorBase, error) {
+	var items []*models.CollectorBase
+	size := pageSize
+	for offset := int32(0); ; offset += size {
+		params := lm.NewGetCollectorListParams()
+		if filter != "" {
+			params.SetFilter(&filter)
+		}
+		params.SetSize(&size)
+		off := offset
+		params.SetOffset(&off)
+		resp, err := sdkGo.LM.GetCollectorList(params)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, resp.Payload.Items...)
+		if lastPage(len(resp.Payload.Items), len(items), resp.Payload.Total) {
+			return items, nil
+		}
+	}
+}
+
+// ListCollectorGroups returns all collector groups matching filter, paging through results
+func ListCollectorGroups(sdkGo *client.LMSdkGo, filter string) ([]*models.CollectorGroup, error) {
+	var items []*models.CollectorGroup
+	size := pageSize
+	for offset := int32(0); ; offset += size {
+		params := lm.NewGetCollectorGroupListParams()
+		if filter != "" {
+			params.SetFilter(&filter)
+		}
+		params.SetSize(&size)
+		off := offset
+		params.SetOffset(&off)
+		resp, err := sdkGo.LM.GetCollectorGroupList(params)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, resp.Payload.Items...)
+		if lastPage(len(resp.Payload.Items), len(items), resp.Payload.Total) {
+			return items, nil
+		}
+	}
+}
+
+// ListEscalationChains returns all escalation chains matching filter, paging through results
+func ListEscalationChains(sdkGo *client.LMSdkGo, filter string) ([]*models.EscalatingChain, error) {
+	var items []*models.EscalatingChain
+	size := pageSize
+	for offset := int32(0); ; offset += size {
+		params := lm.NewGetEscalationChainListParams()
+		if filter != "" {
+			params.SetFilter(&filter)
+		}
+		params.SetSize(&size)
+		off := offset
+		params.SetOffset(&off)
+		resp, err := sdkGo.LM.GetEscalationChainList(params)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, resp.Payload.Items...)
+		if lastPage(len(resp.Payload.Items), len(items), resp.Payload.Total) {
+			return items, nil
+		}
+	}
+}
+
+// ListCollectorVersions returns all available collector versions, paging through results
+func ListCollectorVersions(sdkGo *client.LMSdkGo) ([]*models.CollectorVersion, error) {
+	var items []*models.CollectorVersion
+	size := pageSize
+	for offset := int32(0); ; offset += size {
+		params := lm.NewGetCollectorVersionListParams()
+		params.SetSize(&size)
+		off := offset
+		params.SetOffset(&off)
+		resp, err := sdkGo.LM.GetCollectorVersionList(params)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, resp.Payload.Items...)
+		if lastPage(len(resp.Payload.Items), len(items), resp.Payload.Total) {
+			return items, nil
+		}
+	}
+}
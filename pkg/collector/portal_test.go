@@ -0,0 +1,85 @@
+package collector
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	httptransport "github.com/go-openapi/runtime/client"
+	"github.com/go-openapi/strfmt"
+	"github.com/logicmonitor/lm-sdk-go/client"
+	"github.com/logicmonitor/lm-sdk-go/client/lm"
+	"github.com/logicmonitor/lm-sdk-go/models"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+)
+
+const collectorsPath = "/santaba/rest/setting/collector/collectors"
+
+// fakePortal serves collectors of Logicmonitor collector API and records patches
+type fakePortal struct {
+	mu         sync.Mutex
+	collectors []*models.Collector
+	patches    map[int32]map[string]any
+	// hangPatch leaves patches unanswered until client gives up
+	hangPatch bool
+}
+
+func newFakePortal(t *testing.T, collectors ...*models.Collector) (*fakePortal, *client.LMSdkGo, *config.Creds) {
+	t.Helper()
+	p := &fakePortal{collectors: collectors, patches: map[int32]map[string]any{}}
+	srv := httptest.NewTLSServer(http.HandlerFunc(p.serve))
+	t.Cleanup(srv.Close)
+	transport := httptransport.NewWithClient(strings.TrimPrefix(srv.URL, "https://"), client.DefaultBasePath, []string{"https"}, srv.Client())
+	sdkGo := &client.LMSdkGo{Transport: transport, LM: lm.New(transport, strfmt.Default, nil)}
+	return p, sdkGo, &config.Creds{Account: "acme", AccessID: "id", AccessKey: "key"}
+}
+
+func (p *fakePortal) serve(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPatch && p.hangPatch {
+		// request context is only cancelled on client disconnect once body is read
+		_, _ = io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	if r.URL.Path == collectorsPath {
+		items := make([]*models.CollectorBase, 0, len(p.collectors))
+		for _, c := range p.collectors {
+			items = append(items, &models.CollectorBase{ID: c.ID, Description: c.Description, CustomProperties: c.CustomProperties})
+		}
+		_ = json.NewEncoder(w).Encode(&models.CollectorPaginationResponse{Items: items, Total: int32(len(items))})
+		return
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, collectorsPath+"/"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	for _, c := range p.collectors {
+		if c.ID != int32(id) {
+			continue
+		}
+		if r.Method == http.MethodPatch {
+			body := map[string]any{}
+			b, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(b, &body)
+			p.patches[c.ID] = body
+		}
+		_ = json.NewEncoder(w).Encode(c)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+func (p *fakePortal) patch(id int32) map[string]any {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.patches[id]
+}
@@ -0,0 +1,89 @@
+package collector
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/constants"
+)
+
+// proxyInstallArgs installer arguments of proxy lmbc uses, none without proxy
+func proxyInstallArgs(creds *config.Creds) []string {
+	if creds.Proxy == nil {
+		return nil
+	}
+	args := []string{"-p", creds.ProxyAddr()}
+	if user, pass := creds.ProxyCredentials(); user != "" {
+		args = append(args, "-U", user)
+		if pass != "" {
+			args = append(args, "-P", pass)
+		}
+	}
+	return args
+}
+
+// proxyEnv environment of installer process, so that installer downloads honour proxy and no proxy list too
+func proxyEnv(creds *config.Creds) []string {
+	if creds.Proxy == nil {
+		return nil
+	}
+	proxy := *creds.Proxy
+	proxy.User = nil
+	if user, pass := creds.ProxyCredentials(); user != "" && pass != "" {
+		proxy.User = url.UserPassword(user, pass)
+	} else if user != "" {
+		proxy.User = url.User(user)
+	}
+	env := []string{"HTTP_PROXY=" + proxy.String(), "HTTPS_PROXY=" + proxy.String()}
+	if creds.NoProxy != "" {
+		env = append(env, "NO_PROXY="+noProxyList(creds))
+	}
+	return env
+}
+
+// ProxyConf agent.conf proxy settings matching proxy lmbc uses, settings without value are left out
+func ProxyConf(creds *config.Creds) (*config.CollectorConf, error) {
+	user, pass := creds.ProxyCredentials()
+	settings := []struct {
+		key   string
+		value string
+	}{
+		{"proxy.enable", "true"},
+		{"proxy.host", creds.Proxy.Hostname()},
+		{"proxy.port", creds.ProxyPort()},
+		{"proxy.user", user},
+		{"proxy.pass", pass},
+		{"proxy.exclude", noProxyList(creds)},
+	}
+	// proxy settings aren't discrete per replica, index only satisfies apply engine
+	index := 0
+	cf := &config.CollectorConf{DebugIndex: &index}
+	for _, s := range settings {
+		if s.value == "" {
+			continue
+		}
+		cf.AgentConf = append(cf.AgentConf, &config.KeyValue{Key: s.key, Value: s.value})
+	}
+	if err := cf.Validate(); err != nil {
+		return nil, err
+	}
+	return cf, nil
+}
+
+// ApplyProxyConf writes proxy settings into agent.conf through apply engine
+func ApplyProxyConf(logger logrus.FieldLogger, creds *config.Creds) error {
+	cf, err := ProxyConf(creds)
+	if err != nil {
+		return err
+	}
+	logger.Infof("Writing proxy %s settings into agent.conf", creds.ProxyAddr())
+	return ApplyConf(logger, constants.AgentConfPath, pkg.Properties, cf)
+}
+
+// noProxyList no proxy hosts as comma separated list without blanks
+func noProxyList(creds *config.Creds) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(creds.NoProxy, ",", " ")), ",")
+}
@@ -0,0 +1,154 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-openapi/runtime"
+	"github.com/go-openapi/strfmt"
+	"github.com/logicmonitor/lm-sdk-go/client"
+	"github.com/logicmonitor/lm-sdk-go/models"
+	"github.com/sirupsen/logrus"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+)
+
+// patchTimeout bounds single collector patch, caller context may be cancelled earlier
+const patchTimeout = time.Minute
+
+// Change collector setting differing between portal and configuration
+type Change struct {
+	Field string
+	From  any
+	To    any
+}
+
+// reconciledSetting collector setting reconciled with configuration, keyed by flag name in config.Config.Configured
+type reconciledSetting struct {
+	flag    string
+	field   string
+	desired func(conf *config.Config) any
+	current func(c *models.Collector) any
+}
+
+var reconciledSettings = []reconciledSetting{
+	{
+		flag:    "description",
+		field:   "description",
+		desired: func(conf *config.Config) any { return conf.Description },
+		current: func(c *models.Collector) any { return c.Description },
+	},
+	{
+		flag:    "escalating-chain-id",
+		field:   "escalatingChainId",
+		desired: func(conf *config.Config) any { return conf.EscalatingChainID },
+		current: func(c *models.Collector) any { return c.EscalatingChainID },
+	},
+	{
+		flag:    "resend-interval",
+		field:   "resendIval",
+		desired: func(conf *config.Config) any { return conf.ResendInterval },
+		current: func(c *models.Collector) any { return c.ResendIval },
+	},
+	{
+		flag:    "suppress-alert-clear",
+		field:   "suppressAlertClear",
+		desired: func(conf *config.Config) any { return conf.SuppressAlertClear },
+		current: func(c *models.Collector) any { return c.SuppressAlertClear },
+	},
+	{
+		flag:    "enable-fail-back",
+		field:   "enableFailBack",
+		desired: func(conf *config.Config) any { return conf.EnableFailBack },
+		current: func(c *models.Collector) any { return toBool(c.EnableFailBack) },
+	},
+	{
+		flag:    "backup-collector-id",
+		field:   "backupAgentId",
+		desired: func(conf *config.Config) any { return conf.BackupCollectorID },
+		current: func(c *models.Collector) any { return c.BackupAgentID },
+	},
+}
+
+// Diff compares portal collector with explicitly configured settings, settings left unconfigured are ignored
+func Diff(collector *models.Collector, conf *config.Config) []Change {
+	var changes []Change
+	for _, s := range reconciledSettings {
+		if !conf.Configured[s.flag] {
+			continue
+		}
+		from, to := s.current(collector), s.desired(conf)
+		if from != to {
+			changes = append(changes, Change{Field: s.field, From: from, To: to})
+		}
+	}
+	return changes
+}
+
+// Reconcile patches configured settings differing on existing collector, only logs diff on dry run
+func Reconcile(ctx context.Context, logger logrus.FieldLogger, creds *config.Creds, conf *config.Config, sdkGo *client.LMSdkGo, collector *models.Collector, dryRun bool) ([]Change, error) {
+	changes := Diff(collector, conf)
+	if len(changes) == 0 {
+		logger.Infof("Collector %d settings are up to date", collector.ID)
+		return nil, nil
+	}
+	body := make(map[string]any, len(changes))
+	for _, c := range changes {
+		logger.Infof("Collector %d %s: %v -> %v", collector.ID, c.Field, c.From, c.To)
+		body[c.Field] = c.To
+	}
+	if dryRun {
+		logger.Infof("Dry run, collector %d not updated", collector.ID)
+		return changes, nil
+	}
+	if err := patchCollector(ctx, creds, sdkGo, collector.ID, body); err != nil {
+		return changes, fmt.Errorf("updating collector %d failed with: %w", collector.ID, err)
+	}
+	logger.Infof("Collector %d settings updated", collector.ID)
+	return changes, nil
+}
+
+// patchCollector patches collector with only given fields. models.Collector omits zero values
+// (false, 0) on marshalling which makes it unusable to reset settings, hence raw body is sent.
+func patchCollector(ctx context.Context, creds *config.Creds, sdkGo *client.LMSdkGo, id int32, body map[string]any) error {
+	ctx, cancel := context.WithTimeout(ctx, patchTimeout)
+	defer cancel()
+	_, err := sdkGo.Transport.Submit(&runtime.ClientOperation{
+		ID:                 "patchCollectorById",
+		Method:             http.MethodPatch,
+		PathPattern:        "/setting/collector/collectors/{id}",
+		ProducesMediaTypes: []string{"application/json"},
+		ConsumesMediaTypes: []string{"application/json"},
+		Schemes:            []string{"https"},
+		Params: runtime.ClientRequestWriterFunc(func(r runtime.ClientRequest, _ strfmt.Registry) error {
+			if err := r.SetPathParam("id", strconv.Itoa(int(id))); err != nil {
+				return err
+			}
+			return r.SetBodyParam(body)
+		}),
+		Reader: runtime.ClientResponseReaderFunc(func(resp runtime.ClientResponse, consumer runtime.Consumer) (any, error) {
+			if resp.Code() == http.StatusOK {
+				return nil, nil
+			}
+			payload := &models.ErrorResponse{}
+			_ = consumer.Consume(resp.Body(), payload)
+			return nil, fmt.Errorf("[PATCH /setting/collector/collectors/{id}][%d] patchCollectorById %+v", resp.Code(), payload)
+		}),
+		AuthInfo: client.LMv1Auth(creds.AccessID, creds.AccessKey),
+		Context:  ctx,
+	})
+	return err
+}
+
+func toBool(v any) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		parsed, _ := strconv.ParseBool(b)
+		return parsed
+	}
+	return false
+}
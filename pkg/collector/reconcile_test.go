@@ -0,0 +1,31 @@
+package collector
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/logicmonitor/lm-sdk-go/models"
+	"github.com/sirupsen/logrus"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+)
+
+func TestReconcileGivesUpOnHungPortal(t *testing.T) {
+	portal, sdkGo, creds := newFakePortal(t, &models.Collector{ID: 1, Description: "old"})
+	portal.hangPatch = true
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	conf := &config.Config{Description: "new", Configured: map[string]bool{"description": true}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := Reconcile(ctx, logger, creds, conf, sdkGo, &models.Collector{ID: 1, Description: "old"}, false)
+	if err == nil {
+		t.Fatal("Reconcile() succeeded against hung portal")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Reconcile() returned after %s, want it bound by caller context", elapsed)
+	}
+}
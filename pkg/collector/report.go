@@ -0,0 +1,104 @@
+package collector
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// reportTailLines lines of installer stdout and stderr kept in install report
+const reportTailLines = 20
+
+// InstallReport result of collector installation
+type InstallReport struct {
+	CollectorID      int32     `json:"collectorId"`
+	RequestedVersion string    `json:"requestedVersion,omitempty"`
+	InstalledVersion string    `json:"installedVersion,omitempty"`
+	InstallUser      string    `json:"installUser"`
+	Installer        string    `json:"installer"`
+	StartedAt        time.Time `json:"startedAt"`
+	DurationSeconds  float64   `json:"durationSeconds"`
+	ExitCode         int       `json:"exitCode"`
+	StdoutTail       string    `json:"stdoutTail"`
+	StderrTail       string    `json:"stderrTail"`
+	Error            string    `json:"error,omitempty"`
+}
+
+// WriteInstallReport writes report as json to path
+func WriteInstallReport(path string, report *InstallReport) error {
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o644)
+}
+
+// ReadInstallReport reads report written by earlier installation
+func ReadInstallReport(path string) (*InstallReport, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	report := &InstallReport{}
+	if err := json.Unmarshal(b, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// installedVersion reads version of installed collector from installer's complexInfo line in install.tmp
+func installedVersion(installStatPath string) string {
+	f, err := os.Open(installStatPath)
+	if err != nil {
+		return ""
+	}
+	defer func(f *os.File) {
+		_ = f.Close()
+	}(f)
+	scanner := bufio.NewScanner(f)
+	version := ""
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.Contains(line, "complexInfo=") {
+			continue
+		}
+		complexInfo := struct {
+			InstalledCollector struct {
+				Version any `json:"version"`
+			} `json:"installedCollector"`
+		}{}
+		_, val, _ := strings.Cut(line, "=")
+		if err := json.Unmarshal([]byte(val), &complexInfo); err != nil {
+			continue
+		}
+		if v, ok := complexInfo.InstalledCollector.Version.(string); ok && v != "" {
+			version = v
+		}
+	}
+	return version
+}
+
+// exitCode exit code of failed command, -1 when command didn't run
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// tail last n lines of s
+func tail(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
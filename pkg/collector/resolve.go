@@ -0,0 +1,154 @@
+package collector
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/logicmonitor/lm-sdk-go/client"
+	"github.com/logicmonitor/lm-sdk-go/client/lm"
+	"github.com/logicmonitor/lm-sdk-go/models"
+	"github.com/sirupsen/logrus"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/cerrors"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/util"
+)
+
+// nearMatchesCount number of near matches listed when name doesn't resolve
+const nearMatchesCount = 5
+
+// suggestionPoolSize number of names fetched, in a single page, to pick near matches from
+const suggestionPoolSize = int32(300)
+
+// ResolveReferences resolves escalation chain and backup collector configured by name into IDs,
+// resolved settings are marked configured so that reconcile applies them
+func ResolveReferences(logger logrus.FieldLogger, conf *config.Config, sdkGo *client.LMSdkGo) error {
+	if conf.EscalatingChain != "" {
+		id, err := ResolveEscalationChainID(sdkGo, conf.EscalatingChain)
+		if err != nil {
+			return err
+		}
+		logger.Infof("Escalation chain [%s] resolved to id %d", conf.EscalatingChain, id)
+		conf.EscalatingChainID = id
+		markConfigured(conf, "escalating-chain-id")
+	}
+	if conf.BackupCollector != "" || conf.BackupCollectorProperty != "" {
+		id, err := ResolveBackupCollectorID(conf, sdkGo)
+		if err != nil {
+			return err
+		}
+		logger.Infof("Backup collector resolved to id %d", id)
+		conf.BackupCollectorID = id
+		markConfigured(conf, "backup-collector-id")
+	}
+	return nil
+}
+
+func markConfigured(conf *config.Config, flag string) {
+	if conf.Configured == nil {
+		conf.Configured = map[string]bool{}
+	}
+	conf.Configured[flag] = true
+}
+
+// ResolveEscalationChainID finds escalation chain by exact name
+func ResolveEscalationChainID(sdkGo *client.LMSdkGo, name string) (int32, error) {
+	chains, err := ListEscalationChains(sdkGo, FilterEquals("name", name))
+	if err != nil {
+		return 0, err
+	}
+	for _, c := range chains {
+		if c.Name != nil && *c.Name == name {
+			return c.ID, nil
+		}
+	}
+	names, err := escalationChainNames(sdkGo)
+	if err != nil {
+		return 0, fmt.Errorf("escalation chain [%s] not found", name)
+	}
+	return 0, notResolvedError("escalation chain", name, names)
+}
+
+// ResolveBackupCollectorID finds backup collector by custom property (name=value) or by exact description
+func ResolveBackupCollectorID(conf *config.Config, sdkGo *client.LMSdkGo) (int32, error) {
+	if conf.BackupCollectorProperty != "" {
+		kv := strings.SplitN(conf.BackupCollectorProperty, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return 0, fmt.Errorf("backup collector property must be in name=value format: %s", conf.BackupCollectorProperty)
+		}
+		c, err := FindCollectorByProperty(sdkGo, kv[0], kv[1])
+		if err != nil {
+			return 0, fmt.Errorf("backup collector: %w", err)
+		}
+		return c.ID, nil
+	}
+	list, err := ListCollectors(sdkGo, FilterEquals("description", conf.BackupCollector))
+	if err != nil {
+		return 0, err
+	}
+	var matches []*models.CollectorBase
+	for _, c := range list {
+		if c.Description == conf.BackupCollector {
+			matches = append(matches, c)
+		}
+	}
+	switch len(matches) {
+	case 1:
+		return matches[0].ID, nil
+	case 0:
+		descriptions, err := collectorDescriptions(sdkGo)
+		if err != nil {
+			return 0, fmt.Errorf("backup collector [%s]: %w", conf.BackupCollector, cerrors.CollectorNotFoundError)
+		}
+		return 0, notResolvedError("backup collector", conf.BackupCollector, descriptions)
+	}
+	var ids []string
+	for _, c := range matches {
+		ids = append(ids, fmt.Sprintf("%d", c.ID))
+	}
+	return 0, fmt.Errorf("backup collector [%s] is ambiguous, collectors %s share the description, use backup collector property or id instead",
+		conf.BackupCollector, strings.Join(ids, ", "))
+}
+
+// escalationChainNames names of escalation chains to suggest near matches from, limited to a single page
+func escalationChainNames(sdkGo *client.LMSdkGo) ([]string, error) {
+	params := lm.NewGetEscalationChainListParams()
+	fields, size := "id,name", suggestionPoolSize
+	params.SetFields(&fields)
+	params.SetSize(&size)
+	resp, err := sdkGo.LM.GetEscalationChainList(params)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, c := range resp.Payload.Items {
+		if c.Name != nil {
+			names = append(names, *c.Name)
+		}
+	}
+	return names, nil
+}
+
+// collectorDescriptions descriptions of collectors to suggest near matches from, limited to a single page
+func collectorDescriptions(sdkGo *client.LMSdkGo) ([]string, error) {
+	params := lm.NewGetCollectorListParams()
+	fields, size := "id,description", suggestionPoolSize
+	params.SetFields(&fields)
+	params.SetSize(&size)
+	resp, err := sdkGo.LM.GetCollectorList(params)
+	if err != nil {
+		return nil, err
+	}
+	var descriptions []string
+	for _, c := range resp.Payload.Items {
+		descriptions = append(descriptions, c.Description)
+	}
+	return descriptions, nil
+}
+
+func notResolvedError(kind string, name string, candidates []string) error {
+	near := util.NearMatches(name, candidates, nearMatchesCount)
+	if len(near) == 0 {
+		return fmt.Errorf("%s [%s] not found", kind, name)
+	}
+	return fmt.Errorf("%s [%s] not found, near matches: %q", kind, name, near)
+}
@@ -0,0 +1,133 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/logicmonitor/lm-sdk-go/client"
+	"github.com/logicmonitor/lm-sdk-go/models"
+	"github.com/sirupsen/logrus"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/constants"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/util"
+)
+
+// Upgrade methods
+const (
+	// UpgradeMethodAPI schedules one time upgrade through collector upgrade api, portal pushes new build
+	UpgradeMethodAPI = "api"
+	// UpgradeMethodInstaller runs installer of new build over existing installation
+	UpgradeMethodInstaller = "installer"
+)
+
+// preUpgradeBackupExt extension of agent.conf backup taken before upgrade
+const preUpgradeBackupExt = ".pre-upgrade"
+
+// Upgrade moves collector to requested version and waits until portal reports the new build. When new build
+// never connects, agent.conf taken before upgrade is restored and agent restarted.
+func Upgrade(ctx context.Context, logger logrus.FieldLogger, creds *config.Creds, conf *config.Config, sdkGo *client.LMSdkGo, collector *models.Collector, method string) error {
+	if method != UpgradeMethodAPI && method != UpgradeMethodInstaller {
+		return fmt.Errorf(`unknown upgrade method %q, must be one of "%s" or "%s"`, method, UpgradeMethodAPI, UpgradeMethodInstaller)
+	}
+	if method == UpgradeMethodInstaller {
+		if err := conf.ResolvePlatform(); err != nil {
+			return err
+		}
+	}
+	if err := ResolveVersion(logger, conf, sdkGo); err != nil {
+		return err
+	}
+	if conf.Version == 0 {
+		return errors.New("version to upgrade to must be set")
+	}
+	target := config.FormatVersion(conf.Version)
+	if buildMatches(collector.Build, target) {
+		logger.Infof("Collector %d is already on build %s", collector.ID, collector.Build)
+		return nil
+	}
+	logger.Infof("Upgrading collector %d from build %s to %s using %s", collector.ID, collector.Build, target, method)
+
+	backup := constants.AgentConfPath + preUpgradeBackupExt
+	backedUp := false
+	if exists, _ := util.FileExists(constants.AgentConfPath); exists {
+		if err := util.CopyFile(constants.AgentConfPath, backup); err != nil {
+			return fmt.Errorf("backing up %s failed with: %w", constants.AgentConfPath, err)
+		}
+		backedUp = true
+		logger.Infof("Backed up %s at %s", constants.AgentConfPath, backup)
+	}
+
+	var err error
+	switch method {
+	case UpgradeMethodAPI:
+		err = scheduleUpgrade(ctx, creds, sdkGo, collector.ID, conf.Version)
+	case UpgradeMethodInstaller:
+		var filename string
+		filename, err = DownloadInstaller(logger, creds, conf, sdkGo, collector)
+		if err == nil {
+			_, err = runInstaller(logger, creds, conf, filename)
+		}
+	}
+	if err != nil {
+		removeBackup(logger, backup, backedUp)
+		return fmt.Errorf("upgrading collector %d failed with: %w", collector.ID, err)
+	}
+
+	if _, err := WaitReady(ctx, logger, conf, sdkGo, collector.ID, strconv.Itoa(int(conf.Version))); err != nil {
+		if !backedUp {
+			return fmt.Errorf("%w, no agent.conf backup to roll back to", err)
+		}
+		logger.Warnf("Collector %d didn't come up on build %s, restoring %s", collector.ID, target, constants.AgentConfPath)
+		if rerr := rollback(logger, backup); rerr != nil {
+			// backup is kept for manual restore
+			return fmt.Errorf("%w, rollback failed with: %s, agent.conf backup kept at %s", err, rerr, backup)
+		}
+		removeBackup(logger, backup, backedUp)
+		return fmt.Errorf("%w, agent.conf rolled back", err)
+	}
+	removeBackup(logger, backup, backedUp)
+	logger.Infof("Collector %d upgraded to build %s", collector.ID, target)
+	return nil
+}
+
+// removeBackup removes agent.conf backup once it is no longer needed, so that next upgrade starts clean
+func removeBackup(logger logrus.FieldLogger, backup string, backedUp bool) {
+	if !backedUp {
+		return
+	}
+	if err := os.Remove(backup); err != nil {
+		logger.Warnf("Removing %s failed with: %s", backup, err)
+	}
+}
+
+// scheduleUpgrade schedules one time upgrade starting right away
+func scheduleUpgrade(ctx context.Context, creds *config.Creds, sdkGo *client.LMSdkGo, id int32, version int32) error {
+	return patchCollector(ctx, creds, sdkGo, id, map[string]any{
+		"onetimeUpgradeInfo": map[string]any{
+			"majorVersion": version / 1000,
+			"minorVersion": version % 1000,
+			"startEpoch":   time.Now().Unix(),
+			"timezone":     "UTC",
+			"description":  "lmbc upgrade",
+		},
+	})
+}
+
+// rollback restores agent.conf taken before upgrade and restarts agent to pick it up
+func rollback(logger logrus.FieldLogger, backup string) error {
+	if err := util.CopyFile(backup, constants.AgentConfPath); err != nil {
+		return err
+	}
+	if err, stdout, stderr := util.Shellout(constants.AgentBin, "stop"); err != nil {
+		logger.Warnf("Stopping agent failed with: %s, stdout: %s, stderr: %s", err, stdout, stderr)
+	}
+	if err, stdout, stderr := util.Shellout(constants.AgentBin, "start"); err != nil {
+		return fmt.Errorf("starting agent failed with: %w, stdout: %s, stderr: %s", err, stdout, stderr)
+	}
+	logger.Infof("Restored %s and restarted agent", constants.AgentConfPath)
+	return nil
+}
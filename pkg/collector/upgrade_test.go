@@ -0,0 +1,41 @@
+package collector
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/logicmonitor/lm-sdk-go/models"
+	"github.com/sirupsen/logrus"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/constants"
+)
+
+func TestUpgradeRemovesBackupOnSuccess(t *testing.T) {
+	constants.SetInstallPath(t.TempDir())
+	defer constants.SetInstallPath("")
+	if err := os.MkdirAll(filepath.Dir(constants.AgentConfPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(constants.AgentConfPath, []byte("company=acme\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	isDown := false
+	portal, sdkGo, creds := newFakePortal(t, &models.Collector{ID: 1, Build: "34002", IsDown: &isDown})
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	conf := &config.Config{Version: 34002, WaitTimeout: time.Second, WaitInterval: 10 * time.Millisecond}
+
+	if err := Upgrade(context.Background(), logger, creds, conf, sdkGo, &models.Collector{ID: 1, Build: "34001"}, UpgradeMethodAPI); err != nil {
+		t.Fatal(err)
+	}
+	if portal.patch(1) == nil {
+		t.Error("upgrade is not scheduled")
+	}
+	if _, err := os.Stat(constants.AgentConfPath + preUpgradeBackupExt); err == nil {
+		t.Error("agent.conf backup is left behind after successful upgrade")
+	}
+}
@@ -0,0 +1,108 @@
+package collector
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/logicmonitor/lm-sdk-go/models"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/cerrors"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/util"
+)
+
+// MinInstallerSize smallest size (1 MiB) a downloaded installer must have to be run. Genuine installers
+// bundle jre and agent jars and weigh hundreds of MiB, anything below the threshold is an error page or
+// a truncated download. Threshold is kept far below real sizes so that smaller future builds still pass.
+const MinInstallerSize = 1 << 20
+
+// installerHeaderSize leading bytes of installer inspected for shell header and embedded version
+const installerHeaderSize = 64 << 10
+
+// maxErrorBodySize bytes of api error body surfaced in error
+const maxErrorBodySize = 2 << 10
+
+// shellMagic linux installer is a self extracting shell script
+var shellMagic = []byte("#!")
+
+// exeMagic windows installer is a PE executable
+var exeMagic = []byte("MZ")
+
+// installerVersionRegexp matches version embedded in installer script header, e.g. VERSION="34.001" or version=34001.
+// Installer header layout isn't documented by LogicMonitor, pattern is a best effort guess: version not found is
+// not an error and version mismatch only fails with strict version.
+var installerVersionRegexp = regexp.MustCompile(`(?i)\bversion\W{0,3}(\d{2,3}\.?\d{3})\b`)
+
+// ValidateInstaller checks downloaded installer before it gets executed: shell or executable header of platform,
+// minimum size and, with strict, embedded version matching build when both are known. Error body returned by api
+// in place of installer is surfaced as error. Returns version embedded in installer, empty when not found in which
+// case version check is skipped.
+func ValidateInstaller(platform config.Platform, filename string, build string, strict bool) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	stat, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	header := make([]byte, installerHeaderSize)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	header = header[:n]
+
+	magic, kind := shellMagic, "shell"
+	if platform.Windows {
+		magic, kind = exeMagic, "executable"
+	}
+	if !bytes.HasPrefix(header, magic) {
+		if body := errorBody(header); body != "" {
+			return "", fmt.Errorf("%w: api returned error instead of installer: %s", cerrors.InvalidInstallerError, body)
+		}
+		return "", fmt.Errorf("%w: %s doesn't start with %s header", cerrors.InvalidInstallerError, filename, kind)
+	}
+	if stat.Size() < MinInstallerSize {
+		return "", fmt.Errorf("%w: %s is %s, smaller than %s", cerrors.InvalidInstallerError, filename, util.ToSI(stat.Size()), util.ToSI(MinInstallerSize))
+	}
+	m := installerVersionRegexp.FindSubmatch(header)
+	if m == nil {
+		return "", nil
+	}
+	version := string(m[1])
+	if strict && !buildMatches(version, build) {
+		return version, fmt.Errorf("%w: installer version %s doesn't match requested build %s", cerrors.InvalidInstallerError, version, build)
+	}
+	return version, nil
+}
+
+// errorBody extracts error message of json or html body, empty when content doesn't look like one
+func errorBody(content []byte) string {
+	trimmed := bytes.TrimSpace(content)
+	if len(trimmed) == 0 {
+		return ""
+	}
+	switch trimmed[0] {
+	case '{':
+		resp := &models.ErrorResponse{}
+		if err := json.Unmarshal(trimmed, resp); err == nil && resp.ErrorMessage != "" {
+			return fmt.Sprintf("[%d] %s", resp.ErrorCode, resp.ErrorMessage)
+		}
+	case '<':
+	default:
+		return ""
+	}
+	body := strings.Join(strings.Fields(string(trimmed)), " ")
+	if len(body) > maxErrorBodySize {
+		body = body[:maxErrorBodySize] + "..."
+	}
+	return body
+}
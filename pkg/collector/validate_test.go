@@ -0,0 +1,88 @@
+package collector
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/cerrors"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+)
+
+func writeInstaller(t *testing.T, header string, size int) string {
+	t.Helper()
+	content := []byte(header)
+	if len(content) < size {
+		content = append(content, bytes.Repeat([]byte{0}, size-len(content))...)
+	}
+	filename := filepath.Join(t.TempDir(), "logicmonitorsetup.bin")
+	if err := os.WriteFile(filename, content, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestInstallerVersionExtraction(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "quoted dotted", header: "#!/bin/sh\nVERSION=\"34.001\"\n", want: "34.001"},
+		{name: "build number", header: "#!/bin/sh\nversion=34001\n", want: "34001"},
+		{name: "comment", header: "#!/bin/sh\n# LogicMonitor Collector version: 35.100\n", want: "35.100"},
+		{name: "first of many", header: "#!/bin/sh\nVERSION=34.001\nOLD_VERSION=33.002\n", want: "34.001"},
+		{name: "suffix of other variable", header: "#!/bin/sh\nJAVA_VERSION=11.0.1\n", want: ""},
+		{name: "too short", header: "#!/bin/sh\nversion 1.2\n", want: ""},
+		{name: "absent", header: "#!/bin/sh\necho installing\n", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := writeInstaller(t, tt.header, MinInstallerSize)
+			got, err := ValidateInstaller(config.Platform{}, filename, "", false)
+			if err != nil {
+				t.Fatalf("ValidateInstaller() failed with: %s", err)
+			}
+			if got != tt.want {
+				t.Errorf("ValidateInstaller() version = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateInstaller(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform config.Platform
+		header   string
+		size     int
+		build    string
+		strict   bool
+		wantErr  bool
+	}{
+		{name: "matching build", header: "#!/bin/sh\nVERSION=34.001\n", size: MinInstallerSize, build: "34001"},
+		{name: "version not found skips check", header: "#!/bin/sh\n", size: MinInstallerSize, build: "34001"},
+		{name: "mismatching build", header: "#!/bin/sh\nVERSION=34.001\n", size: MinInstallerSize, build: "34002"},
+		{name: "mismatching build with strict", header: "#!/bin/sh\nVERSION=34.001\n", size: MinInstallerSize, build: "34002", strict: true, wantErr: true},
+		{name: "matching build with strict", header: "#!/bin/sh\nVERSION=34.001\n", size: MinInstallerSize, build: "34001", strict: true},
+		{name: "undersized", header: "#!/bin/sh\n", size: MinInstallerSize - 1, wantErr: true},
+		{name: "json error body", header: `{"errorMessage":"Authentication failed","errorCode":1401}`, wantErr: true},
+		{name: "html error body", header: "<html><body>502 Bad Gateway</body></html>", wantErr: true},
+		{name: "windows executable", platform: config.Platform{Windows: true}, header: "MZ", size: MinInstallerSize},
+		{name: "shell script for windows", platform: config.Platform{Windows: true}, header: "#!/bin/sh\n", size: MinInstallerSize, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := writeInstaller(t, tt.header, tt.size)
+			_, err := ValidateInstaller(tt.platform, filename, tt.build, tt.strict)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateInstaller() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, cerrors.InvalidInstallerError) {
+				t.Errorf("ValidateInstaller() error = %v, want %v", err, cerrors.InvalidInstallerError)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package collector
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/logicmonitor/lm-sdk-go/client"
+	"github.com/logicmonitor/lm-sdk-go/models"
+	"github.com/sirupsen/logrus"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/cerrors"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+)
+
+// versionChannel channel collector version is released on
+func versionChannel(v *models.CollectorVersion) string {
+	switch {
+	case v.Mandatory != nil && *v.Mandatory:
+		return config.ChannelMGD
+	case v.Stable != nil && *v.Stable:
+		return config.ChannelGA
+	}
+	return config.ChannelEA
+}
+
+// channelAllows reports whether version released on given channel can be picked from channel
+func channelAllows(channel string, released string) bool {
+	switch channel {
+	case config.ChannelEA:
+		return true
+	case config.ChannelGA:
+		return released != config.ChannelEA
+	}
+	return released == config.ChannelMGD
+}
+
+// ResolveVersion picks highest available collector version matching configured version constraint and
+// channel, and sets it as requested version. Each version preferred over the chosen one is logged with
+// the reason it was rejected. Without strict version, falls back to latest version of channel when
+// nothing matches. Nothing is done when neither version nor channel is configured.
+func ResolveVersion(logger logrus.FieldLogger, conf *config.Config, sdkGo *client.LMSdkGo) error {
+	if conf.VersionSpec == "" && conf.Channel == "" {
+		return nil
+	}
+	versions, err := ListCollectorVersions(sdkGo)
+	if err != nil {
+		return fmt.Errorf("listing collector versions failed with: %w", err)
+	}
+	v, err := selectVersion(logger, conf, versions)
+	if err != nil {
+		return err
+	}
+	conf.Version = v
+	return nil
+}
+
+// selectVersion picks highest version of versions matching version constraint and channel, see ResolveVersion
+func selectVersion(logger logrus.FieldLogger, conf *config.Config, versions []*models.CollectorVersion) (int32, error) {
+	sort.Slice(versions, func(i, j int) bool {
+		return buildNumber(versions[i]) > buildNumber(versions[j])
+	})
+
+	channel := conf.ReleaseChannel()
+	var latest int32
+	for _, v := range versions {
+		n := buildNumber(v)
+		released := versionChannel(v)
+		reason := ""
+		switch {
+		case !channelAllows(channel, released):
+			reason = fmt.Sprintf("released on %s channel, %s channel requested", released, channel)
+		case conf.Platform.Is32Bit && !conf.Platform.Windows && (v.Has32bitLinux == nil || !*v.Has32bitLinux):
+			reason = "no 32 bit linux installer"
+		case conf.Platform.Is32Bit && conf.Platform.Windows && (v.Has32bitWindows == nil || !*v.Has32bitWindows):
+			reason = "no 32 bit windows installer"
+		}
+		if reason == "" && latest == 0 {
+			latest = n
+		}
+		if reason == "" && conf.VersionConstraint != nil {
+			if ok, term := conf.VersionConstraint.Check(n); !ok {
+				reason = "doesn't satisfy " + term
+			}
+		}
+		if reason != "" {
+			logger.Infof("Rejected collector version %s: %s", config.FormatVersion(n), reason)
+			continue
+		}
+		if conf.VersionConstraint != nil {
+			logger.Infof("Chose collector version %s: highest %s version satisfying %s", config.FormatVersion(n), channel, conf.VersionConstraint)
+		} else {
+			logger.Infof("Chose collector version %s: latest %s version", config.FormatVersion(n), channel)
+		}
+		return n, nil
+	}
+
+	if latest == 0 {
+		return 0, fmt.Errorf("%w: no %s collector version available", cerrors.VersionError, channel)
+	}
+	if conf.StrictVersion {
+		return 0, fmt.Errorf("%w: no %s collector version satisfies %s", cerrors.VersionError, channel, conf.VersionConstraint)
+	}
+	logger.Warnf("No %s collector version satisfies %s, falling back to latest %s version %s", channel, conf.VersionConstraint, channel, config.FormatVersion(latest))
+	return latest, nil
+}
+
+func buildNumber(v *models.CollectorVersion) int32 {
+	return v.MajorVersion*1000 + v.MinorVersion
+}
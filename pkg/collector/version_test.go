@@ -0,0 +1,80 @@
+package collector
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/logicmonitor/lm-sdk-go/models"
+	"github.com/sirupsen/logrus"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/cerrors"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+)
+
+func collectorVersion(major, minor int32, released string) *models.CollectorVersion {
+	mandatory, stable := released == config.ChannelMGD, released != config.ChannelEA
+	return &models.CollectorVersion{MajorVersion: major, MinorVersion: minor, Mandatory: &mandatory, Stable: &stable}
+}
+
+func TestSelectVersion(t *testing.T) {
+	versions := []*models.CollectorVersion{
+		collectorVersion(33, 2, config.ChannelMGD),
+		collectorVersion(34, 1, config.ChannelMGD),
+		collectorVersion(34, 100, config.ChannelGA),
+		collectorVersion(35, 0, config.ChannelGA),
+		collectorVersion(35, 100, config.ChannelEA),
+	}
+	tests := []struct {
+		name    string
+		spec    string
+		channel string
+		useEa   bool
+		strict  bool
+		want    int32
+		wantErr error
+	}{
+		{name: "latest ga", channel: config.ChannelGA, want: 35000},
+		{name: "latest ea", channel: config.ChannelEA, want: 35100},
+		{name: "use ea defaults to ea channel", spec: ">=35", useEa: true, want: 35100},
+		{name: "latest mandatory", channel: config.ChannelMGD, want: 34001},
+		{name: "major range", spec: "34", want: 34100},
+		{name: "major range on mandatory channel", spec: "34", channel: config.ChannelMGD, want: 34001},
+		{name: "upper bound", spec: "<35.000", want: 34100},
+		{name: "exclusion", spec: ">=34 <35 !=34.100", want: 34001},
+		{name: "exact", spec: "33.002", want: 33002},
+		{name: "ea version refused on ga channel", spec: "35.100", want: 35000},
+		{name: "strict refuses fallback", spec: "35.100", strict: true, wantErr: cerrors.VersionError},
+		{name: "fallback to latest", spec: ">=36", want: 35000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &config.Config{VersionSpec: tt.spec, Channel: tt.channel, UseEa: tt.useEa, StrictVersion: tt.strict}
+			if tt.spec != "" {
+				c, err := config.ParseVersionConstraint(tt.spec)
+				if err != nil {
+					t.Fatal(err)
+				}
+				conf.VersionConstraint = c
+			}
+			logger := logrus.New()
+			logger.SetOutput(io.Discard)
+			got, err := selectVersion(logger, conf, versions)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("selectVersion() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("selectVersion() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectVersionNothingOnChannel(t *testing.T) {
+	versions := []*models.CollectorVersion{collectorVersion(35, 100, config.ChannelEA)}
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	_, err := selectVersion(logger, &config.Config{Channel: config.ChannelGA}, versions)
+	if !errors.Is(err, cerrors.VersionError) {
+		t.Errorf("selectVersion() error = %v, want %v", err, cerrors.VersionError)
+	}
+}
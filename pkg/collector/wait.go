@@ -0,0 +1,106 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/logicmonitor/lm-sdk-go/client"
+	"github.com/logicmonitor/lm-sdk-go/client/lm"
+	"github.com/logicmonitor/lm-sdk-go/models"
+	"github.com/sirupsen/logrus"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/cerrors"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+)
+
+// ExpectedBuild returns build collector should report once it is up: installed build when known,
+// otherwise requested version. Empty when any build is fine.
+func ExpectedBuild(conf *config.Config, collector *models.Collector) string {
+	if collector != nil && collector.Build != "" && collector.Build != "0" {
+		return collector.Build
+	}
+	if conf.Version != 0 {
+		return strconv.Itoa(int(conf.Version))
+	}
+	return ""
+}
+
+// WaitReady polls collector until portal reports it up with expected build, or timeout expires.
+// On timeout returns cerrors.CollectorNotReadyError with last seen collector status.
+func WaitReady(ctx context.Context, logger logrus.FieldLogger, conf *config.Config, sdkGo *client.LMSdkGo, collectorID int32, build string) (*models.Collector, error) {
+	ctx, cancel := context.WithTimeout(ctx, conf.WaitTimeout)
+	defer cancel()
+	logger.Infof("Waiting up to %s for collector %d to be up%s", conf.WaitTimeout, collectorID, buildSuffix(build))
+
+	var last *models.Collector
+	var lastErr error
+	ticker := time.NewTicker(conf.WaitInterval)
+	defer ticker.Stop()
+	for {
+		params := lm.NewGetCollectorByIDParams()
+		params.SetID(collectorID)
+		resp, err := sdkGo.LM.GetCollectorByID(params)
+		if err != nil {
+			logger.Warnf("Getting collector %d failed with: %s", collectorID, err)
+			lastErr = err
+		} else {
+			last, lastErr = resp.Payload, nil
+			if isUp(last) && buildMatches(last.Build, build) {
+				logger.Infof("Collector %d is up, build %s", collectorID, last.Build)
+				return last, nil
+			}
+			logger.Infof("Collector %d not ready yet: %s", collectorID, StatusSummary(last))
+		}
+		select {
+		case <-ctx.Done():
+			if last == nil {
+				return nil, fmt.Errorf("%w: collector %d not fetched within %s, last error: %v", cerrors.CollectorNotReadyError, collectorID, conf.WaitTimeout, lastErr)
+			}
+			return last, fmt.Errorf("%w: collector %d not up%s within %s, %s", cerrors.CollectorNotReadyError, collectorID, buildSuffix(build), conf.WaitTimeout, StatusSummary(last))
+		case <-ticker.C:
+		}
+	}
+}
+
+// StatusSummary describes collector status as seen by portal
+func StatusSummary(c *models.Collector) string {
+	down := "unknown"
+	if c.IsDown != nil {
+		down = strconv.FormatBool(*c.IsDown)
+	}
+	heartbeat := c.UpdatedOnLocal
+	if heartbeat == "" {
+		heartbeat = "never"
+	}
+	return fmt.Sprintf("isDown: %s, status: %d, build: %s, upTime: %ds, last heartbeat: %s, watchdog updated: %s",
+		down, c.Status, c.Build, c.UpTime, heartbeat, c.WatchdogUpdatedOnLocal)
+}
+
+func isUp(c *models.Collector) bool {
+	return c.IsDown != nil && !*c.IsDown
+}
+
+// buildMatches compares builds ignoring format differences, e.g. 34.001 and 34001
+func buildMatches(build string, want string) bool {
+	return want == "" || normalizeBuild(build) == normalizeBuild(want)
+}
+
+func normalizeBuild(build string) string {
+	major, minor, ok := strings.Cut(strings.TrimSpace(build), ".")
+	if !ok {
+		return major
+	}
+	for len(minor) < 3 {
+		minor = "0" + minor
+	}
+	return major + minor
+}
+
+func buildSuffix(build string) string {
+	if build == "" {
+		return ""
+	}
+	return " with build " + build
+}
@@ -3,10 +3,12 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net"
 	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type CollectorSize uint32
@@ -76,6 +78,8 @@ type Creds struct {
 	ProxyPass string
 	IgnoreSSL bool
 	SudoPass  string
+	// NoProxy comma separated hosts collector reaches without proxy
+	NoProxy string
 
 	// created internally from proxy parameters
 	Proxy *url.URL
@@ -108,9 +112,40 @@ func (c *Creds) parseProxy() error {
 		return err
 	}
 
+	if c.Proxy.Scheme == "" || c.Proxy.Hostname() == "" {
+		return fmt.Errorf("proxy url must be in scheme://host[:port] format: %s", c.ProxyUrl)
+	}
 	return nil
 }
 
+// ProxyPort port of proxy, defaults to well known port of proxy scheme
+func (c *Creds) ProxyPort() string {
+	if port := c.Proxy.Port(); port != "" {
+		return port
+	}
+	if c.Proxy.Scheme == "https" {
+		return "443"
+	}
+	return "80"
+}
+
+// ProxyAddr proxy address with scheme and port, e.g. http://proxy:3128
+func (c *Creds) ProxyAddr() string {
+	return c.Proxy.Scheme + "://" + net.JoinHostPort(c.Proxy.Hostname(), c.ProxyPort())
+}
+
+// ProxyCredentials proxy user and password, proxy user and pass take precedence over proxy url user info
+func (c *Creds) ProxyCredentials() (string, string) {
+	if c.ProxyUser != "" {
+		return c.ProxyUser, c.ProxyPass
+	}
+	if c.Proxy.User != nil {
+		pass, _ := c.Proxy.User.Password()
+		return c.Proxy.User.Username(), pass
+	}
+	return "", ""
+}
+
 // Config Start ShutDown Config
 type Config struct {
 	BackupCollectorID  int32
@@ -126,6 +161,58 @@ type Config struct {
 	SuppressAlertClear bool
 	UseEa              bool
 	Kubernetes         bool
+	AutoRegister       bool
+	Properties         map[string]string
+
+	EscalatingChain         string
+	BackupCollector         string
+	BackupCollectorProperty string
+
+	GroupID          int32
+	CreateGroup      bool
+	GroupDescription string
+	GroupProperties  map[string]string
+
+	CreateDevice      bool
+	DeviceGroup       string
+	DeviceGroupID     int32
+	DeviceDisplayName string
+	DeviceCategories  []string
+	DeviceProperties  map[string]string
+	DeviceWaitTimeout time.Duration
+
+	InstanceID         string
+	InstanceIDSource   string
+	InstanceIDProperty string
+
+	// VersionSpec version flag, exact version or constraint like ">=34.000 <35.000"
+	VersionSpec       string
+	VersionConstraint VersionConstraint `json:"-"`
+	Channel           string
+	StrictVersion     bool
+
+	InstallerPath     string
+	InstallerURL      string
+	InstallerUser     string
+	InstallerPass     string `json:"-"`
+	InstallerCABundle string
+
+	// OsArch installer platform override, e.g. Linux64, resolved into Platform
+	OsArch   string
+	Platform Platform `json:"-"`
+
+	InstallReportPath string
+
+	InstallerCacheDir  string
+	InstallerCacheSize int64
+
+	Wait         bool
+	WaitTimeout  time.Duration
+	WaitInterval time.Duration
+	Build        string
+
+	// Configured settings explicitly set by flag, env or config file, keyed by flag name
+	Configured map[string]bool
 
 	IDS         string
 	Debug       bool
@@ -140,11 +227,22 @@ func (c *Config) Validate() error {
 		// fmt.Println("setting default collector size to small")
 		c.Size = Small
 	}
-	if !c.Kubernetes && c.ID == 0 && c.Description == "" {
-		return fmt.Errorf(`\"collector_id\" or \"description\" must be set in non kubernetes environments`)
+	if err := c.renderTemplates(); err != nil {
+		return err
+	}
+	if c.Kubernetes && c.AutoRegister {
+		if err := c.setK8sIdentity(); err != nil {
+			return err
+		}
+	}
+	if err := c.resolveInstanceID(); err != nil {
+		return err
+	}
+	if !c.Kubernetes && c.ID == 0 && c.Description == "" && c.InstanceID == "" {
+		return fmt.Errorf(`\"collector_id\", \"description\" or \"instance_id\" must be set in non kubernetes environments`)
 	}
 
-	if c.Kubernetes {
+	if c.Kubernetes && !c.AutoRegister {
 		if err := c.setK8sCollectorID(); err != nil {
 			return err
 		}
@@ -154,6 +252,16 @@ func (c *Config) Validate() error {
 	//        if 'extra_large' in kwargs['collector_size'] or 'double_extra_large' in kwargs['collector_size']:
 	//            err = 'Cannot proceed with installation because only Early Access collector versions support ' + kwargs[
 	//                'collector_size'] + 'size. To proceed further with installation, set \"use_ea\" parameter to true or use appropriate collector size.\n'
+	if err := c.resolveVersionSpec(); err != nil {
+		return err
+	}
+	if c.InstallerPath != "" && c.InstallerURL != "" {
+		return fmt.Errorf("only one of installer path and installer url can be set")
+	}
+	if c.WaitInterval <= 0 {
+		c.WaitInterval = 15 * time.Second
+	}
+
 	if !c.UseEa && (c.Size == ExtraLarge || c.Size == DoubleExtraLarge) {
 		err := fmt.Errorf("cannot proceed with installation because only Early Access collector versions support " + c.Size.String() + " size. To proceed further with installation, set \"use_ea\" parameter to true or use appropriate collector size")
 		return err
@@ -162,16 +270,17 @@ func (c *Config) Validate() error {
 	return nil
 }
 
-func (c *Config) setK8sCollectorID() error {
-	var err error
-	var index int
+func (c *Config) k8sIndex() (int, error) {
 	if c.Debug {
-		index = c.DebugIndex
-	} else {
-		index, err = GetCollectorIndex()
-		if err != nil {
-			return err
-		}
+		return c.DebugIndex, nil
+	}
+	return GetCollectorIndex()
+}
+
+func (c *Config) setK8sCollectorID() error {
+	index, err := c.k8sIndex()
+	if err != nil {
+		return err
 	}
 
 	ids := strings.Split(c.IDS, ",")
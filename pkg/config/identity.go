@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultInstanceIDProperty collector custom property holding instance id
+const DefaultInstanceIDProperty = "lmbc.instance-id"
+
+// PodUIDEnv env var populated with pod uid using kubernetes downward api (metadata.uid)
+const PodUIDEnv = "POD_UID"
+
+// PodNamespaceEnv env var populated with pod namespace using kubernetes downward api (metadata.namespace)
+const PodNamespaceEnv = "POD_NAMESPACE"
+
+// serviceAccountNamespaceFile namespace of mounted service account, fallback when namespace env is not set
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// Instance id sources
+const (
+	InstanceIDSourceNone      = ""
+	InstanceIDSourceMachineID = "machine-id"
+	InstanceIDSourcePodUID    = "pod-uid"
+)
+
+var machineIDFiles = []string{"/etc/machine-id", "/var/lib/dbus/machine-id"}
+
+// resolveInstanceID derives instance id from configured source when it isn't set explicitly
+func (c *Config) resolveInstanceID() error {
+	if c.InstanceIDProperty == "" {
+		c.InstanceIDProperty = DefaultInstanceIDProperty
+	}
+	if c.InstanceID != "" {
+		return nil
+	}
+	switch c.InstanceIDSource {
+	case InstanceIDSourceNone:
+		return nil
+	case InstanceIDSourceMachineID:
+		for _, f := range machineIDFiles {
+			b, err := os.ReadFile(f)
+			if err != nil {
+				continue
+			}
+			if id := strings.TrimSpace(string(b)); id != "" {
+				c.InstanceID = id
+				return nil
+			}
+		}
+		return fmt.Errorf("machine id not found in %v", machineIDFiles)
+	case InstanceIDSourcePodUID:
+		id := os.Getenv(PodUIDEnv)
+		if id == "" {
+			return fmt.Errorf("pod uid env %s is not set, expose metadata.uid through downward api", PodUIDEnv)
+		}
+		c.InstanceID = id
+		return nil
+	}
+	return fmt.Errorf(`unknown instance id source %q, must be one of "%s" or "%s"`, c.InstanceIDSource, InstanceIDSourceMachineID, InstanceIDSourcePodUID)
+}
+
+// setK8sIdentity derives collector identity of statefulset replica from namespace, statefulset name and ordinal.
+// Instance id survives pod rescheduling, so replica finds its own collector again.
+func (c *Config) setK8sIdentity() error {
+	index, err := c.k8sIndex()
+	if err != nil {
+		return err
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		return err
+	}
+	statefulSet := hostname
+	if i := strings.LastIndex(hostname, "-"); i > 0 {
+		statefulSet = hostname[:i]
+	}
+	namespace, err := podNamespace()
+	if err != nil {
+		return err
+	}
+	if c.InstanceID == "" && c.InstanceIDSource == InstanceIDSourceNone {
+		c.InstanceID = fmt.Sprintf("%s/%s/%d", namespace, statefulSet, index)
+	}
+	if c.Description == "" {
+		c.Description = fmt.Sprintf("%s/%s-%d", namespace, statefulSet, index)
+	}
+	return nil
+}
+
+func podNamespace() (string, error) {
+	if ns := os.Getenv(PodNamespaceEnv); ns != "" {
+		return ns, nil
+	}
+	b, err := os.ReadFile(serviceAccountNamespaceFile)
+	if err != nil {
+		return "", fmt.Errorf("pod namespace env %s is not set, expose metadata.namespace through downward api: %w", PodNamespaceEnv, err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
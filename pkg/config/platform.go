@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/constants"
+)
+
+// Platform installer platform as named by collector installer api
+type Platform struct {
+	// OsAndArch installer api value, e.g. Linux64
+	OsAndArch string
+	// Arch installer file name architecture, e.g. x64
+	Arch string
+	// Ext installer file extension
+	Ext string
+	// Is32Bit 32 bit installer, only some collector versions ship one
+	Is32Bit bool
+	// Windows installer can only be downloaded, not installed by lmbc
+	Windows bool
+}
+
+// platforms installers available through installer api
+var platforms = map[string]Platform{
+	"Linux64": {OsAndArch: "Linux64", Arch: "x64", Ext: "bin"},
+	"Linux32": {OsAndArch: "Linux32", Arch: "x86", Ext: "bin", Is32Bit: true},
+	"Win64":   {OsAndArch: "Win64", Arch: "x64", Ext: "exe", Windows: true},
+	"Win32":   {OsAndArch: "Win32", Arch: "x86", Ext: "exe", Is32Bit: true, Windows: true},
+}
+
+// hostPlatforms maps GOOS/GOARCH to installer platforms runnable on host, first one is the default
+var hostPlatforms = map[string][]string{
+	"linux/amd64":   {"Linux64", "Linux32"},
+	"linux/386":     {"Linux32"},
+	"windows/amd64": {"Win64", "Win32"},
+	"windows/386":   {"Win32"},
+}
+
+// unsupportedHosts hosts lmbc runs on for which no collector installer is published
+var unsupportedHosts = map[string]string{
+	"linux/arm64": "LogicMonitor publishes no arm64 collector installer",
+}
+
+// ResolvePlatform selects installer platform from os-arch override or host os and architecture,
+// fails for platforms without collector installer. Installer of another platform than host's can only
+// be downloaded with skip-install, it is refused before download otherwise.
+func (c *Config) ResolvePlatform() error {
+	return c.resolvePlatform(runtime.GOOS + "/" + runtime.GOARCH)
+}
+
+func (c *Config) resolvePlatform(host string) error {
+	runnable, hostSupported := hostPlatforms[host]
+	hostErr := fmt.Errorf("collector installer is not available for %s, supported platforms: %s", host, supportedPlatforms())
+	if reason, ok := unsupportedHosts[host]; ok {
+		hostErr = fmt.Errorf("collector installer is not available for %s: %s", host, reason)
+	}
+	osAndArch := c.OsArch
+	if osAndArch == "" {
+		if !hostSupported {
+			return fmt.Errorf("%w. Use os-arch with skip-install to download installer of another platform", hostErr)
+		}
+		osAndArch = runnable[0]
+	}
+	platform, ok := platforms[osAndArch]
+	if !ok {
+		return fmt.Errorf("unsupported os-arch %q, must be one of %s", osAndArch, supportedPlatforms())
+	}
+	if !c.SkipInstall {
+		if !hostSupported {
+			return fmt.Errorf("%w. %s installer can't run here, use skip-install to only download it", hostErr, osAndArch)
+		}
+		if !contains(runnable, osAndArch) {
+			return fmt.Errorf("%s installer can't run on %s, use skip-install to only download it", osAndArch, host)
+		}
+		if platform.Windows {
+			return fmt.Errorf("%s installer can't be installed by lmbc, use skip-install to only download it", osAndArch)
+		}
+	}
+	c.Platform = platform
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// InstallerFileName local file name of downloaded installer, e.g. /tmp/logicmonitorsetupx64_12.bin
+func (c *Config) InstallerFileName() string {
+	return fmt.Sprintf("%slogicmonitorsetup%s_%d.%s", constants.TempPath, c.Platform.Arch, c.ID, c.Platform.Ext)
+}
+
+func supportedPlatforms() string {
+	var names []string
+	for name := range platforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestResolvePlatform(t *testing.T) {
+	tests := []struct {
+		name        string
+		host        string
+		osArch      string
+		skipInstall bool
+		want        string
+		wantErr     bool
+	}{
+		{name: "host default", host: "linux/amd64", want: "Linux64"},
+		{name: "32 bit host", host: "linux/386", want: "Linux32"},
+		{name: "32 bit installer on 64 bit host", host: "linux/amd64", osArch: "Linux32", want: "Linux32"},
+		{name: "arm64 host", host: "linux/arm64", wantErr: true},
+		{name: "x64 installer on arm64 host", host: "linux/arm64", osArch: "Linux64", wantErr: true},
+		{name: "x64 installer downloaded on arm64 host", host: "linux/arm64", osArch: "Linux64", skipInstall: true, want: "Linux64"},
+		{name: "64 bit installer on 32 bit host", host: "linux/386", osArch: "Linux64", wantErr: true},
+		{name: "windows installer on linux host", host: "linux/amd64", osArch: "Win64", wantErr: true},
+		{name: "windows installer downloaded on linux host", host: "linux/amd64", osArch: "Win64", skipInstall: true, want: "Win64"},
+		{name: "windows installer on windows host", host: "windows/amd64", wantErr: true},
+		{name: "unknown os-arch", host: "linux/amd64", osArch: "Linux128", skipInstall: true, wantErr: true},
+		{name: "unknown host", host: "darwin/arm64", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{OsArch: tt.osArch, SkipInstall: tt.skipInstall}
+			err := c.resolvePlatform(tt.host)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolvePlatform(%s) error = %v, wantErr %v", tt.host, err, tt.wantErr)
+			}
+			if c.Platform.OsAndArch != tt.want {
+				t.Errorf("platform = %q, want %q", c.Platform.OsAndArch, tt.want)
+			}
+		})
+	}
+}
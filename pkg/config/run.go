@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"os/user"
+	"time"
+
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/constants"
+)
+
+// RunConfig long-running supervisor config
+type RunConfig struct {
+	SkipBootstrap            bool
+	PollInterval             time.Duration
+	WatchdogStartTimeout     time.Duration
+	AgentFailureThreshold    int
+	WatchdogFailureThreshold int
+	ShutdownTimeout          time.Duration
+	StreamLogs               bool
+	HealthAddr               string
+	RestartLimit             int
+	RestartBackoff           time.Duration
+	RestartMaxBackoff        time.Duration
+	// ProcessUser user agent and watchdog service scripts and sbshutdown run as, current user when empty
+	ProcessUser string
+}
+
+// LogFiles collector log files streamed on stdout in long-running mode
+func (rc *RunConfig) LogFiles() []string {
+	return []string{
+		constants.AgentLogPath + "wrapper.log",
+		constants.AgentLogPath + "watchdog.log",
+		constants.AgentLogPath + "sbproxy.log",
+	}
+}
+
+func (rc *RunConfig) Validate() error {
+	if rc.PollInterval <= 0 {
+		return fmt.Errorf("poll interval must be positive: %s", rc.PollInterval)
+	}
+	if rc.WatchdogStartTimeout <= 0 {
+		return fmt.Errorf("watchdog start timeout must be positive: %s", rc.WatchdogStartTimeout)
+	}
+	if rc.AgentFailureThreshold < 1 {
+		return fmt.Errorf("agent failure threshold must be at least 1: %d", rc.AgentFailureThreshold)
+	}
+	if rc.WatchdogFailureThreshold < 1 {
+		return fmt.Errorf("watchdog failure threshold must be at least 1: %d", rc.WatchdogFailureThreshold)
+	}
+	if rc.RestartLimit < 0 {
+		return fmt.Errorf("restart limit cannot be negative: %d", rc.RestartLimit)
+	}
+	if rc.RestartBackoff <= 0 {
+		return fmt.Errorf("restart backoff must be positive: %s", rc.RestartBackoff)
+	}
+	if rc.RestartMaxBackoff < rc.RestartBackoff {
+		return fmt.Errorf("restart max backoff %s cannot be less than restart backoff %s", rc.RestartMaxBackoff, rc.RestartBackoff)
+	}
+	if rc.ShutdownTimeout <= 0 {
+		return fmt.Errorf("shutdown timeout must be positive: %s", rc.ShutdownTimeout)
+	}
+	if rc.ProcessUser != "" {
+		if _, err := user.Lookup(rc.ProcessUser); err != nil {
+			return fmt.Errorf("process user: %w", err)
+		}
+	}
+	return nil
+}
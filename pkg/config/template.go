@@ -0,0 +1,78 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// TemplateData data available to description and custom property templates,
+// e.g. {{.Env.CLUSTER}}-{{.Hostname}}-{{.Index}}
+type TemplateData struct {
+	Hostname string
+	Env      map[string]string
+	Size     string
+	Group    string
+
+	index func() (int, error)
+}
+
+// Index collector index parsed from hostname, only evaluated when template refers it
+func (d TemplateData) Index() (int, error) {
+	if d.index == nil {
+		return GetCollectorIndex()
+	}
+	return d.index()
+}
+
+// TemplateData returns template data of configuration
+func (c *Config) TemplateData() TemplateData {
+	hostname, _ := os.Hostname()
+	env := map[string]string{}
+	for _, kv := range os.Environ() {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+	return TemplateData{
+		Hostname: hostname,
+		Env:      env,
+		Size:     c.Size.String(),
+		Group:    c.Group,
+		index:    c.k8sIndex,
+	}
+}
+
+// Render renders go template text with given data, missing keys fail rendering
+func Render(name string, text string, data any) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parsing %s template failed with: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("rendering %s failed with: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// renderTemplates renders description and custom property templates, so that both lookups and
+// created collector use same values
+func (c *Config) renderTemplates() error {
+	data := c.TemplateData()
+	var err error
+	if c.Description, err = Render("description", c.Description, data); err != nil {
+		return err
+	}
+	for k, v := range c.Properties {
+		if c.Properties[k], err = Render("property "+k, v, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Collector release channels
+const (
+	// ChannelGA general availability releases, including mandatory ones
+	ChannelGA = "ga"
+	// ChannelEA early access releases on top of general availability ones
+	ChannelEA = "ea"
+	// ChannelMGD mandatory general availability releases only
+	ChannelMGD = "mgd"
+)
+
+// versionOperators supported constraint operators, longer operators first so that prefix match is unambiguous
+var versionOperators = []string{">=", "<=", "!=", ">", "<", "="}
+
+// versionTerm single constraint term, e.g. >=34.000
+type versionTerm struct {
+	op      string
+	version int32
+}
+
+// VersionConstraint space separated terms collector version must satisfy, e.g. ">=34.000 <35.000"
+type VersionConstraint []versionTerm
+
+// ParseVersionConstraint parses version constraint, bare version means exact match. Bare major version
+// stands for whole major release: 34 matches any 34.xxx, >34 means >=35.000 and <=34 means <35.000.
+func ParseVersionConstraint(s string) (VersionConstraint, error) {
+	var constraint VersionConstraint
+	for _, field := range strings.Fields(s) {
+		op := "="
+		for _, o := range versionOperators {
+			if strings.HasPrefix(field, o) {
+				op, field = o, strings.TrimPrefix(field, o)
+				break
+			}
+		}
+		v, err := ParseVersion(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version constraint %q: %w", s, err)
+		}
+		if !isMajorVersion(field) {
+			constraint = append(constraint, versionTerm{op: op, version: v})
+			continue
+		}
+		next := v + 1000
+		switch op {
+		case "=":
+			constraint = append(constraint, versionTerm{op: ">=", version: v}, versionTerm{op: "<", version: next})
+		case ">":
+			constraint = append(constraint, versionTerm{op: ">=", version: next})
+		case "<=":
+			constraint = append(constraint, versionTerm{op: "<", version: next})
+		case ">=", "<":
+			constraint = append(constraint, versionTerm{op: op, version: v})
+		default:
+			return nil, fmt.Errorf("invalid version constraint %q: %s%s, exclude version as major.minor", s, op, field)
+		}
+	}
+	if len(constraint) == 0 {
+		return nil, fmt.Errorf("empty version constraint")
+	}
+	return constraint, nil
+}
+
+// isMajorVersion reports whether version is written as major version only, e.g. 34
+func isMajorVersion(s string) bool {
+	v, err := strconv.ParseInt(s, 10, 32)
+	return err == nil && v < 1000
+}
+
+// Exact returns version when constraint pins single version
+func (c VersionConstraint) Exact() (int32, bool) {
+	if len(c) == 1 && c[0].op == "=" {
+		return c[0].version, true
+	}
+	return 0, false
+}
+
+// Check reports whether version satisfies constraint, returns violated term otherwise
+func (c VersionConstraint) Check(v int32) (bool, string) {
+	for _, t := range c {
+		var ok bool
+		switch t.op {
+		case ">=":
+			ok = v >= t.version
+		case "<=":
+			ok = v <= t.version
+		case ">":
+			ok = v > t.version
+		case "<":
+			ok = v < t.version
+		case "!=":
+			ok = v != t.version
+		default:
+			ok = v == t.version
+		}
+		if !ok {
+			return false, t.op + FormatVersion(t.version)
+		}
+	}
+	return true, ""
+}
+
+func (c VersionConstraint) String() string {
+	terms := make([]string, 0, len(c))
+	for _, t := range c {
+		terms = append(terms, t.op+FormatVersion(t.version))
+	}
+	return strings.Join(terms, " ")
+}
+
+// ParseVersion parses collector version written as major.minor (34.001), build number (34001) or major (34),
+// major version alone is its first build, e.g. 34 is 34000
+func ParseVersion(s string) (int32, error) {
+	major, minor, dotted := strings.Cut(s, ".")
+	if !dotted {
+		v, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid version %q", s)
+		}
+		if v < 1000 {
+			// major version only, e.g. 34
+			v *= 1000
+		}
+		return int32(v), nil
+	}
+	ma, err := strconv.ParseInt(major, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid version %q", s)
+	}
+	mi, err := strconv.ParseInt(minor, 10, 32)
+	if err != nil || mi >= 1000 {
+		return 0, fmt.Errorf("invalid version %q", s)
+	}
+	return int32(ma*1000 + mi), nil
+}
+
+// FormatVersion formats build number as major.minor, e.g. 34001 as 34.001
+func FormatVersion(v int32) string {
+	return fmt.Sprintf("%d.%03d", v/1000, v%1000)
+}
+
+// resolveVersionSpec parses version flag, exact version is set on Version right away whereas ranges
+// are resolved against available collector versions before install
+func (c *Config) resolveVersionSpec() error {
+	switch c.Channel {
+	case "", ChannelGA, ChannelEA, ChannelMGD:
+	default:
+		return fmt.Errorf(`unknown channel %q, must be one of "%s", "%s" or "%s"`, c.Channel, ChannelGA, ChannelEA, ChannelMGD)
+	}
+	if c.VersionSpec == "" {
+		return nil
+	}
+	constraint, err := ParseVersionConstraint(c.VersionSpec)
+	if err != nil {
+		return err
+	}
+	c.VersionConstraint = constraint
+	if v, ok := constraint.Exact(); ok {
+		c.Version = v
+	}
+	return nil
+}
+
+// ReleaseChannel channel versions are picked from, defaults to ea with use ea and ga otherwise
+func (c *Config) ReleaseChannel() string {
+	if c.Channel != "" {
+		return c.Channel
+	}
+	if c.UseEa {
+		return ChannelEA
+	}
+	return ChannelGA
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int32
+		wantErr bool
+	}{
+		{in: "34.001", want: 34001},
+		{in: "34001", want: 34001},
+		{in: "34", want: 34000},
+		{in: "34.1", want: 34001},
+		{in: "34.1000", wantErr: true},
+		{in: "v34", wantErr: true},
+		{in: "34.x", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseVersion(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseVersion(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseVersion(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVersionConstraint(t *testing.T) {
+	tests := []struct {
+		spec      string
+		want      string
+		exact     int32
+		match     []int32
+		mismatch  []int32
+		wantError bool
+	}{
+		{spec: "34001", want: "=34.001", exact: 34001, match: []int32{34001}, mismatch: []int32{34000, 34002}},
+		{spec: "34.001", want: "=34.001", exact: 34001, match: []int32{34001}, mismatch: []int32{34002}},
+		{spec: "34", want: ">=34.000 <35.000", match: []int32{34000, 34500, 34999}, mismatch: []int32{33999, 35000}},
+		{spec: ">34", want: ">=35.000", match: []int32{35000}, mismatch: []int32{34999}},
+		{spec: "<=34", want: "<35.000", match: []int32{34999}, mismatch: []int32{35000}},
+		{spec: ">=34 <35", want: ">=34.000 <35.000", match: []int32{34001}, mismatch: []int32{33999, 35000}},
+		{spec: ">=34.000 <35.000 !=34.100", want: ">=34.000 <35.000 !=34.100", match: []int32{34001}, mismatch: []int32{34100}},
+		{spec: "!=34", wantError: true},
+		{spec: ">=abc", wantError: true},
+		{spec: " ", wantError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			c, err := ParseVersionConstraint(tt.spec)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("ParseVersionConstraint(%q) error = %v, wantError %v", tt.spec, err, tt.wantError)
+			}
+			if err != nil {
+				return
+			}
+			if c.String() != tt.want {
+				t.Errorf("String() = %q, want %q", c.String(), tt.want)
+			}
+			if v, ok := c.Exact(); v != tt.exact || ok != (tt.exact != 0) {
+				t.Errorf("Exact() = %d, %v, want %d", v, ok, tt.exact)
+			}
+			for _, v := range tt.match {
+				if ok, term := c.Check(v); !ok {
+					t.Errorf("Check(%d) violates %s, want match", v, term)
+				}
+			}
+			for _, v := range tt.mismatch {
+				if ok, _ := c.Check(v); ok {
+					t.Errorf("Check(%d) matches, want mismatch", v)
+				}
+			}
+		})
+	}
+}
+
+func TestReleaseChannel(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel string
+		useEa   bool
+		want    string
+	}{
+		{name: "default", want: ChannelGA},
+		{name: "use ea", useEa: true, want: ChannelEA},
+		{name: "explicit channel wins over use ea", channel: ChannelMGD, useEa: true, want: ChannelMGD},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Config{Channel: tt.channel, UseEa: tt.useEa}
+			if got := c.ReleaseChannel(); got != tt.want {
+				t.Errorf("ReleaseChannel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
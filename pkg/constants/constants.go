@@ -1,23 +1,77 @@
 package constants
 
+import "strings"
+
 const (
 	// AgentDirectory Agent Directory
 	AgentDirectory = "agent/"
 	// BinPath bin directory
-	BinPath     = "bin/"
-	ConfPath    = "conf/"
-	InstallPath = "/usr/local/logicmonitor/"
+	BinPath  = "bin/"
+	ConfPath = "conf/"
+	// DefaultInstallPath install root unless overridden with SetInstallPath
+	DefaultInstallPath = "/usr/local/logicmonitor/"
 
-	DefaultOs       = "Linux"
-	TempPath        = "/tmp/"
-	InstallStatPath = InstallPath + AgentDirectory + "/tmp/install.tmp"
+	DefaultOs = "Linux"
+	TempPath  = "/tmp/"
+	// InstallerCachePath default directory of cached installers
+	InstallerCachePath = TempPath + "lmbc-installer-cache/"
+)
 
-	LockPath       = InstallPath + AgentDirectory + BinPath
-	LogFile        = InstallPath + "/logs/wrapper.log"
-	CollectorFound = InstallPath + "collector.found"
-	FirstRun       = InstallPath + "first.run"
+// Paths derived from install root, see SetInstallPath
+var (
+	InstallPath     string
+	InstallStatPath string
+	// AgentConfPath agent.conf of installed collector
+	AgentConfPath string
+
+	LockPath       string
+	LogFile        string
+	CollectorFound string
+	FirstRun       string
+	// InstallReportPath json report of last installation
+	InstallReportPath string
+
+	// AgentBin agent service script
+	AgentBin string
+	// WatchdogBin watchdog service script
+	WatchdogBin string
+	// AgentPidFile pid file written by agent java process
+	AgentPidFile string
+	// WatchdogPidFile pid file written by watchdog java process
+	WatchdogPidFile string
+	// SbShutdownBin gracefully stops agent and watchdog
+	SbShutdownBin string
+	// AgentLogPath agent, watchdog and sbproxy logs directory
+	AgentLogPath string
 )
 
+func init() {
+	SetInstallPath(DefaultInstallPath)
+}
+
+// SetInstallPath relocates install root, every install path is derived from it
+func SetInstallPath(dir string) {
+	if dir == "" {
+		dir = DefaultInstallPath
+	}
+	InstallPath = strings.TrimSuffix(dir, "/") + "/"
+	InstallStatPath = InstallPath + AgentDirectory + "tmp/install.tmp"
+	AgentConfPath = InstallPath + AgentDirectory + ConfPath + "agent.conf"
+
+	LockPath = InstallPath + AgentDirectory + BinPath
+	LogFile = InstallPath + "logs/wrapper.log"
+	CollectorFound = InstallPath + "collector.found"
+	FirstRun = InstallPath + "first.run"
+	InstallReportPath = InstallPath + "install-report.json"
+
+	AgentBin = LockPath + "logicmonitor-agent"
+	WatchdogBin = LockPath + "logicmonitor-watchdog"
+	AgentPidFile = LockPath + "logicmonitor-agent.java.pid"
+	WatchdogPidFile = LockPath + "logicmonitor-watchdog.java.pid"
+	SbShutdownBin = LockPath + "sbshutdown"
+	AgentLogPath = InstallPath + AgentDirectory + "logs/"
+}
+
 const (
 	// MinNonRootInstallVer
 	// TODO: this var and the logic that depends on it can be removed after non-root
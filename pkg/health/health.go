@@ -0,0 +1,144 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/supervisor"
+)
+
+// State bootstrap state shared between long-running mode and probe handlers
+type State struct {
+	mu           sync.RWMutex
+	bootstrapped bool
+	collectorID  int32
+}
+
+// SetBootstrapped marks collector installation and configuration apply as finished
+func (s *State) SetBootstrapped(collectorID int32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bootstrapped = true
+	s.collectorID = collectorID
+}
+
+func (s *State) get() (bool, int32) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.bootstrapped, s.collectorID
+}
+
+type processStatus struct {
+	Pid    int  `json:"pid"`
+	Alive  bool `json:"alive"`
+	GaveUp bool `json:"gaveUp"`
+}
+
+type status struct {
+	Status       string                   `json:"status"`
+	Bootstrapped bool                     `json:"bootstrapped"`
+	CollectorID  int32                    `json:"collectorId"`
+	Processes    map[string]processStatus `json:"processes"`
+}
+
+// Server embedded http server serving kubernetes liveness (/healthz) and readiness (/readyz) probes
+type Server struct {
+	logger    logrus.FieldLogger
+	state     *State
+	processes []*supervisor.Process
+	mux       *http.ServeMux
+	srv       *http.Server
+}
+
+func NewServer(logger logrus.FieldLogger, addr string, state *State, processes ...*supervisor.Process) *Server {
+	s := &Server{
+		logger:    logger,
+		state:     state,
+		processes: processes,
+		mux:       http.NewServeMux(),
+	}
+	s.mux.HandleFunc("/healthz", s.healthz)
+	s.mux.HandleFunc("/readyz", s.readyz)
+	s.srv = &http.Server{
+		Addr:              addr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return s
+}
+
+// Handle registers additional handler on server
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Start serves in background, listen errors other than server close are logged
+func (s *Server) Start() {
+	go func() {
+		s.logger.Infof("Serving health endpoints on %s", s.srv.Addr)
+		if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Errorf("Health server failed with: %s", err)
+		}
+	}()
+}
+
+func (s *Server) Stop(ctx context.Context) error {
+	return s.srv.Shutdown(ctx)
+}
+
+// status returns probe status, whether agent and watchdog are running and whether supervisor gave up on either
+func (s *Server) status() (status, bool, bool) {
+	bootstrapped, collectorID := s.state.get()
+	st := status{
+		Bootstrapped: bootstrapped,
+		CollectorID:  collectorID,
+		Processes:    make(map[string]processStatus, len(s.processes)),
+	}
+	allAlive, gaveUp := true, false
+	for _, p := range s.processes {
+		pid, alive := p.Alive()
+		st.Processes[p.Name] = processStatus{Pid: pid, Alive: alive, GaveUp: p.GaveUp()}
+		allAlive = allAlive && alive
+		gaveUp = gaveUp || p.GaveUp()
+	}
+	return st, allAlive, gaveUp
+}
+
+// healthz fails only when supervisor gave up restarting agent or watchdog, so that pod isn't restarted
+// while collector is being installed or while supervisor is restarting a process
+func (s *Server) healthz(w http.ResponseWriter, _ *http.Request) {
+	st, _, gaveUp := s.status()
+	code := http.StatusOK
+	if gaveUp {
+		code = http.StatusServiceUnavailable
+	}
+	s.write(w, code, st)
+}
+
+// readyz fails until collector is installed, configured and agent and watchdog are running, and while
+// either of them is down
+func (s *Server) readyz(w http.ResponseWriter, _ *http.Request) {
+	st, allAlive, _ := s.status()
+	code := http.StatusOK
+	if !st.Bootstrapped || !allAlive {
+		code = http.StatusServiceUnavailable
+	}
+	s.write(w, code, st)
+}
+
+func (s *Server) write(w http.ResponseWriter, code int, st status) {
+	st.Status = "ok"
+	if code != http.StatusOK {
+		st.Status = "unavailable"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(st); err != nil {
+		s.logger.Debugf("Writing health response failed with: %s", err)
+	}
+}
@@ -0,0 +1,71 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/supervisor"
+)
+
+func TestProbes(t *testing.T) {
+	tests := []struct {
+		name         string
+		bootstrapped bool
+		agentAlive   bool
+		gaveUp       bool
+		wantHealthz  int
+		wantReadyz   int
+	}{
+		{name: "bootstrapping", wantHealthz: http.StatusOK, wantReadyz: http.StatusServiceUnavailable},
+		{name: "running", bootstrapped: true, agentAlive: true, wantHealthz: http.StatusOK, wantReadyz: http.StatusOK},
+		{name: "agent down while restarting", bootstrapped: true, wantHealthz: http.StatusOK, wantReadyz: http.StatusServiceUnavailable},
+		{name: "supervisor gave up", bootstrapped: true, gaveUp: true, wantHealthz: http.StatusServiceUnavailable, wantReadyz: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := logrus.New()
+			logger.SetOutput(io.Discard)
+			dir := t.TempDir()
+			alivePidFile := filepath.Join(dir, "alive.pid")
+			if err := os.WriteFile(alivePidFile, []byte(fmt.Sprintf("%d", os.Getpid())), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			s := supervisor.New(logger, &config.RunConfig{PollInterval: time.Millisecond})
+			s.Watchdog.PidFile = alivePidFile
+			s.Agent.PidFile = filepath.Join(dir, "agent.pid")
+			if tt.agentAlive {
+				s.Agent.PidFile = alivePidFile
+			}
+			if tt.gaveUp {
+				// no restart attempts, agent is given up on first failed check
+				s.Agent.FailureThreshold = 1
+				if err := s.Watch(context.Background()); err == nil {
+					t.Fatal("Watch() of dead agent must fail")
+				}
+			}
+			state := &State{}
+			if tt.bootstrapped {
+				state.SetBootstrapped(1)
+			}
+			srv := NewServer(logger, "", state, s.Agent, s.Watchdog)
+
+			for path, want := range map[string]int{"/healthz": tt.wantHealthz, "/readyz": tt.wantReadyz} {
+				rec := httptest.NewRecorder()
+				srv.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+				if rec.Code != want {
+					t.Errorf("%s = %d, want %d: %s", path, rec.Code, want, rec.Body.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package logstream
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Follow follows all files until context is done and emits every line with "source" field
+// set to file name without extension, e.g. wrapper, watchdog or sbproxy
+func Follow(ctx context.Context, logger logrus.FieldLogger, pollInterval time.Duration, files ...string) {
+	var wg sync.WaitGroup
+	for _, f := range files {
+		wg.Add(1)
+		go func(f string) {
+			defer wg.Done()
+			source := strings.TrimSuffix(filepath.Base(f), filepath.Ext(f))
+			t := &tailer{
+				path:         f,
+				pollInterval: pollInterval,
+				logger:       logger.WithField("source", source),
+			}
+			t.follow(ctx)
+		}(f)
+	}
+	wg.Wait()
+}
+
+type tailer struct {
+	path         string
+	pollInterval time.Duration
+	logger       logrus.FieldLogger
+
+	file   *os.File
+	reader *bufio.Reader
+	offset int64
+	// partial line read before EOF, emitted when its newline arrives
+	partial string
+}
+
+func (t *tailer) follow(ctx context.Context) {
+	defer t.close()
+	// lines already present in file when we start are not ours to stream
+	fromStart := false
+	ticker := time.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+	for {
+		if t.file == nil {
+			if err := t.open(fromStart); err != nil && !errors.Is(err, os.ErrNotExist) {
+				t.logger.Debugf("Cannot open log file %s: %s", t.path, err)
+			}
+			// file (re)created later is new content, stream it from start
+			fromStart = true
+		}
+		if t.file != nil {
+			t.readLines()
+			t.checkRotation()
+		}
+		select {
+		case <-ctx.Done():
+			t.readLines()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *tailer) open(fromStart bool) error {
+	f, err := os.Open(t.path)
+	if err != nil {
+		return err
+	}
+	offset := int64(0)
+	if !fromStart {
+		offset, err = f.Seek(0, io.SeekEnd)
+		if err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+	t.file, t.reader, t.offset, t.partial = f, bufio.NewReader(f), offset, ""
+	return nil
+}
+
+func (t *tailer) close() {
+	if t.file != nil {
+		_ = t.file.Close()
+		t.file, t.reader = nil, nil
+	}
+}
+
+func (t *tailer) readLines() {
+	if t.reader == nil {
+		return
+	}
+	for {
+		line, err := t.reader.ReadString('\n')
+		t.offset += int64(len(line))
+		if err != nil {
+			t.partial += line
+			return
+		}
+		t.emit(t.partial + line)
+		t.partial = ""
+	}
+}
+
+func (t *tailer) emit(line string) {
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return
+	}
+	t.logger.Info(line)
+}
+
+// checkRotation reopens file when it is replaced by a new file (rotation)
+// and rewinds when it is truncated in place (copytruncate)
+func (t *tailer) checkRotation() {
+	current, err := os.Stat(t.path)
+	if err != nil {
+		// rotated away and new file not created yet, keep old handle until it appears
+		return
+	}
+	opened, err := t.file.Stat()
+	if err != nil {
+		t.close()
+		return
+	}
+	if !os.SameFile(current, opened) {
+		t.readLines()
+		t.flushPartial()
+		t.close()
+		if err := t.open(true); err != nil {
+			t.logger.Debugf("Cannot reopen rotated log file %s: %s", t.path, err)
+		}
+		return
+	}
+	if current.Size() < t.offset {
+		t.flushPartial()
+		if _, err := t.file.Seek(0, io.SeekStart); err != nil {
+			t.close()
+			return
+		}
+		t.reader.Reset(t.file)
+		t.offset = 0
+	}
+}
+
+func (t *tailer) flushPartial() {
+	if t.partial != "" {
+		t.emit(t.partial)
+		t.partial = ""
+	}
+}
@@ -0,0 +1,152 @@
+package logstream
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func appendFile(t *testing.T, path string, content string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func messages(hook *test.Hook) []string {
+	var lines []string
+	for _, e := range hook.AllEntries() {
+		lines = append(lines, e.Message)
+	}
+	return lines
+}
+
+func TestTailer(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(t *testing.T, path string)
+		want   []string
+	}{
+		{
+			name: "append",
+			change: func(t *testing.T, path string) {
+				appendFile(t, path, "a\nb\n")
+			},
+			want: []string{"before", "a", "b"},
+		},
+		{
+			name: "partial line completed later",
+			change: func(t *testing.T, path string) {
+				appendFile(t, path, "hal")
+				appendFile(t, path, "f\n")
+			},
+			want: []string{"before", "half"},
+		},
+		{
+			name: "rotation",
+			change: func(t *testing.T, path string) {
+				if err := os.Rename(path, path+".1"); err != nil {
+					t.Fatal(err)
+				}
+				// writer still holds rotated file
+				appendFile(t, path+".1", "late\n")
+				appendFile(t, path, "new\n")
+			},
+			want: []string{"before", "late", "new"},
+		},
+		{
+			name: "rotation flushes partial line",
+			change: func(t *testing.T, path string) {
+				appendFile(t, path, "half")
+				if err := os.Rename(path, path+".1"); err != nil {
+					t.Fatal(err)
+				}
+				appendFile(t, path, "new\n")
+			},
+			want: []string{"before", "half", "new"},
+		},
+		{
+			name: "copytruncate",
+			change: func(t *testing.T, path string) {
+				if err := os.Truncate(path, 0); err != nil {
+					t.Fatal(err)
+				}
+				appendFile(t, path, "x\n")
+			},
+			want: []string{"before", "x"},
+		},
+		{
+			name: "rotated away, not recreated yet",
+			change: func(t *testing.T, path string) {
+				if err := os.Rename(path, path+".1"); err != nil {
+					t.Fatal(err)
+				}
+				appendFile(t, path+".1", "late\n")
+			},
+			want: []string{"before", "late"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "wrapper.log")
+			appendFile(t, path, "existing line\n")
+			logger, hook := test.NewNullLogger()
+			tl := &tailer{path: path, logger: logger}
+			if err := tl.open(false); err != nil {
+				t.Fatal(err)
+			}
+			defer tl.close()
+			appendFile(t, path, "before\n")
+			tl.readLines()
+
+			tt.change(t, path)
+			tl.readLines()
+			tl.checkRotation()
+			tl.readLines()
+
+			if got := messages(hook); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("streamed %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFollowSetsSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "watchdog.log")
+	logger, hook := test.NewNullLogger()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Follow(ctx, logger, 10*time.Millisecond, path)
+	}()
+
+	// lines written before follow opens file are skipped, keep writing until one gets streamed
+	deadline := time.Now().Add(5 * time.Second)
+	for len(hook.AllEntries()) == 0 && time.Now().Before(deadline) {
+		appendFile(t, path, "started\n")
+		time.Sleep(20 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	entries := hook.AllEntries()
+	if len(entries) == 0 || entries[0].Message != "started" {
+		t.Fatalf("streamed %q, want [started]", messages(hook))
+	}
+	if source := entries[0].Data["source"]; source != "watchdog" {
+		t.Errorf("source = %v, want watchdog", source)
+	}
+}
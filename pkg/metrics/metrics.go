@@ -0,0 +1,170 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Bootstrap phases
+const (
+	PhaseFindCollector     = "find_collector"
+	PhaseNewCollector      = "new_collector"
+	PhaseDownloadInstaller = "download_installer"
+	PhaseInstall           = "install"
+	PhaseApplyConf         = "apply_conf"
+)
+
+var (
+	PhaseDuration = newVec("lmbc_bootstrap_phase_duration_seconds", "Duration of last run of bootstrap phase.", "gauge", "phase")
+	PhaseTotal    = newVec("lmbc_bootstrap_phase_total", "Bootstrap phase runs by result.", "counter", "phase", "result")
+	InstallerSize = newVec("lmbc_installer_size_bytes", "Size of last downloaded collector installer.", "gauge")
+	// VersionFallbackTotal retries with latest collector version after requested version was rejected
+	VersionFallbackTotal = newVec("lmbc_version_fallback_retries_total", "Installer download retries with latest version after version error.", "counter")
+	ProcessRestartsTotal = newVec("lmbc_process_restarts_total", "Agent and watchdog restarts seen by supervisor.", "counter", "process")
+	APIRequestsTotal     = newVec("lmbc_lm_api_requests_total", "Logicmonitor API calls by status code.", "counter", "code")
+)
+
+var registry = []*Vec{
+	PhaseDuration,
+	PhaseTotal,
+	InstallerSize,
+	VersionFallbackTotal,
+	ProcessRestartsTotal,
+	APIRequestsTotal,
+}
+
+// Vec metric family with fixed label names
+type Vec struct {
+	name       string
+	help       string
+	typ        string
+	labelNames []string
+
+	mu      sync.Mutex
+	samples map[string]float64
+}
+
+func newVec(name, help, typ string, labelNames ...string) *Vec {
+	v := &Vec{
+		name:       name,
+		help:       help,
+		typ:        typ,
+		labelNames: labelNames,
+		samples:    map[string]float64{},
+	}
+	// metric without labels is exposed from the start
+	if len(labelNames) == 0 {
+		v.samples[""] = 0
+	}
+	return v
+}
+
+// Add adds delta to sample identified by label values
+func (v *Vec) Add(delta float64, labelValues ...string) {
+	key := v.key(labelValues)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.samples[key] += delta
+}
+
+// Inc increments sample identified by label values
+func (v *Vec) Inc(labelValues ...string) {
+	v.Add(1, labelValues...)
+}
+
+// Set sets sample identified by label values
+func (v *Vec) Set(val float64, labelValues ...string) {
+	key := v.key(labelValues)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.samples[key] = val
+}
+
+func (v *Vec) key(labelValues []string) string {
+	if len(labelValues) != len(v.labelNames) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", v.name, len(v.labelNames), len(labelValues)))
+	}
+	if len(labelValues) == 0 {
+		return ""
+	}
+	pairs := make([]string, 0, len(labelValues))
+	for i, lv := range labelValues {
+		pairs = append(pairs, v.labelNames[i]+"="+strconv.Quote(lv))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func (v *Vec) write(w io.Writer) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", v.name, v.help, v.name, v.typ); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(v.samples))
+	for k := range v.samples {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", v.name, k, strconv.FormatFloat(v.samples[k], 'g', -1, 64)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ObservePhase records duration and result of bootstrap phase started at start
+func ObservePhase(phase string, start time.Time, err error) {
+	PhaseDuration.Set(time.Since(start).Seconds(), phase)
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	PhaseTotal.Inc(phase, result)
+}
+
+// WriteText writes all metrics in prometheus text exposition format
+func WriteText(w io.Writer) error {
+	for _, v := range registry {
+		if err := v.write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler serves metrics in prometheus text exposition format
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = WriteText(w)
+	})
+}
+
+// InstrumentRoundTripper counts Logicmonitor API calls by response status code
+func InstrumentRoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			APIRequestsTotal.Inc("error")
+			return resp, err
+		}
+		APIRequestsTotal.Inc(strconv.Itoa(resp.StatusCode))
+		return resp, nil
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,73 @@
+package supervisor
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Process java process managed by collector service script and tracked through its pid file
+type Process struct {
+	Name             string
+	Bin              string
+	PidFile          string
+	FailureThreshold int
+
+	pid         int
+	failures    int
+	restarts    int
+	restartedAt time.Time
+	// gaveUp set once process stays down after all restart attempts, read by health probes
+	gaveUp int32
+}
+
+// ReadPid reads pid from pid file, returns 0 when pid file doesn't exist yet
+func ReadPid(pidFile string) (int, error) {
+	b, err := os.ReadFile(pidFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	s := strings.TrimSpace(string(b))
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(s)
+}
+
+// IsAlive checks process existence by sending signal 0, same as "ps <pid>"
+func IsAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = p.Signal(syscall.Signal(0))
+	if err == nil {
+		return true
+	}
+	// process exists but owned by other user
+	return errors.Is(err, syscall.EPERM)
+}
+
+// Alive reads current pid from pid file and reports its liveness
+func (p *Process) Alive() (int, bool) {
+	pid, err := ReadPid(p.PidFile)
+	if err != nil {
+		return 0, false
+	}
+	return pid, IsAlive(pid)
+}
+
+// GaveUp reports whether supervisor gave up on process after exhausting its restart attempts
+func (p *Process) GaveUp() bool {
+	return atomic.LoadInt32(&p.gaveUp) == 1
+}
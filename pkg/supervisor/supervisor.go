@@ -0,0 +1,207 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/cerrors"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/constants"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/metrics"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/util"
+)
+
+// Exit codes of long-running mode
+const (
+	ExitOK              = 0
+	ExitFailure         = 1
+	ExitBootstrapFailed = 2
+	ExitAgentCrashed    = 3
+	ExitWatchdogCrashed = 4
+	ExitShutdownFailed  = 5
+)
+
+// ExitCode maps supervisor error to process exit code
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, cerrors.AgentCrashedError):
+		return ExitAgentCrashed
+	case errors.Is(err, cerrors.WatchdogCrashedError):
+		return ExitWatchdogCrashed
+	}
+	return ExitFailure
+}
+
+type Supervisor struct {
+	logger   logrus.FieldLogger
+	conf     *config.RunConfig
+	Agent    *Process
+	Watchdog *Process
+}
+
+func New(logger logrus.FieldLogger, conf *config.RunConfig) *Supervisor {
+	return &Supervisor{
+		logger: logger,
+		conf:   conf,
+		Agent: &Process{
+			Name:             "agent",
+			Bin:              constants.AgentBin,
+			PidFile:          constants.AgentPidFile,
+			FailureThreshold: conf.AgentFailureThreshold,
+		},
+		Watchdog: &Process{
+			Name:             "watchdog",
+			Bin:              constants.WatchdogBin,
+			PidFile:          constants.WatchdogPidFile,
+			FailureThreshold: conf.WatchdogFailureThreshold,
+		},
+	}
+}
+
+// Start stops agent and watchdog started by installer so that we can control startup,
+// then starts watchdog (which starts agent) and waits for watchdog pid file
+func (s *Supervisor) Start(ctx context.Context) error {
+	for _, p := range []*Process{s.Agent, s.Watchdog} {
+		if err, stdout, stderr := s.shellout(context.Background(), p.Bin, "stop"); err != nil {
+			s.logger.Debugf("Stopping %s failed with: %s, stdout: %s, stderr: %s", p.Name, err, stdout, stderr)
+		}
+	}
+	if err, _, _ := s.shellout(context.Background(), s.Watchdog.Bin, "start"); err != nil {
+		return fmt.Errorf("starting watchdog failed with: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.conf.WatchdogStartTimeout)
+	defer cancel()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		if pid, err := ReadPid(s.Watchdog.PidFile); err == nil && pid != 0 {
+			s.Watchdog.pid = pid
+			s.logger.Infof("Watchdog started with pid %d", pid)
+			return nil
+		}
+		s.logger.Info("Waiting for watchdog to start")
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("watchdog pid file [%s] not found: %w", s.Watchdog.PidFile, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// Watch polls agent and watchdog pid files until context is done or
+// one of the processes stays dead for its failure threshold after all restart attempts
+func (s *Supervisor) Watch(ctx context.Context) error {
+	ticker := time.NewTicker(s.conf.PollInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.check(ctx, s.Watchdog, cerrors.WatchdogCrashedError); err != nil {
+			return err
+		}
+		if err := s.check(ctx, s.Agent, cerrors.AgentCrashedError); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Supervisor) check(ctx context.Context, p *Process, crashErr error) error {
+	pid, alive := p.Alive()
+	if alive {
+		if pid != p.pid {
+			s.logger.Infof("Found new %s pid %d", p.Name, pid)
+			if p.pid != 0 {
+				metrics.ProcessRestartsTotal.Inc(p.Name)
+			}
+			p.pid = pid
+		}
+		p.failures = 0
+		// process recovered and stayed up long enough, give it full restart budget again
+		if p.restarts > 0 && time.Since(p.restartedAt) > s.conf.RestartMaxBackoff {
+			s.logger.Infof("%s running since %s, resetting restart attempts", p.Name, p.restartedAt.Format(time.RFC3339))
+			p.restarts = 0
+		}
+		return nil
+	}
+	p.failures++
+	reason := fmt.Sprintf("pid %d is not running", pid)
+	if pid == 0 {
+		reason = fmt.Sprintf("pid file %s not found", p.PidFile)
+	}
+	s.logger.Warnf("%s is not running: %s (failures: %d/%d)", p.Name, reason, p.failures, p.FailureThreshold)
+	if p.failures < p.FailureThreshold {
+		return nil
+	}
+	reason = fmt.Sprintf("%s for %d consecutive checks", reason, p.failures)
+	if p.restarts >= s.conf.RestartLimit {
+		atomic.StoreInt32(&p.gaveUp, 1)
+		return fmt.Errorf("%s down after %d restart attempts, %s: %w", p.Name, p.restarts, reason, crashErr)
+	}
+	return s.restart(ctx, p, reason)
+}
+
+// restart restarts process through its service script after exponential backoff
+func (s *Supervisor) restart(ctx context.Context, p *Process, reason string) error {
+	backoff := s.backoff(p.restarts)
+	p.restarts++
+	s.logger.WithField("reason", reason).Warnf("Restarting %s in %s (attempt %d/%d)", p.Name, backoff, p.restarts, s.conf.RestartLimit)
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-timer.C:
+	}
+	if err, stdout, stderr := s.shellout(context.Background(), p.Bin, "stop"); err != nil {
+		s.logger.Debugf("Stopping %s failed with: %s, stdout: %s, stderr: %s", p.Name, err, stdout, stderr)
+	}
+	if err, stdout, stderr := s.shellout(context.Background(), p.Bin, "start"); err != nil {
+		s.logger.Warnf("Starting %s failed with: %s, stdout: %s, stderr: %s", p.Name, err, stdout, stderr)
+	}
+	p.failures = 0
+	p.restartedAt = time.Now()
+	return nil
+}
+
+// backoff doubles restart backoff on every attempt, capped at max backoff
+func (s *Supervisor) backoff(attempt int) time.Duration {
+	backoff := s.conf.RestartBackoff
+	for i := 0; i < attempt; i++ {
+		backoff *= 2
+		if backoff >= s.conf.RestartMaxBackoff {
+			return s.conf.RestartMaxBackoff
+		}
+	}
+	return backoff
+}
+
+// shellout runs service script or sbshutdown as process user, as current user when not configured
+func (s *Supervisor) shellout(ctx context.Context, command string, args ...string) (error, string, string) {
+	return util.ShelloutAsContext(ctx, s.conf.ProcessUser, command, args...)
+}
+
+// Stop gracefully stops agent and watchdog through sbshutdown, waits at most shutdown timeout
+func (s *Supervisor) Stop() error {
+	s.logger.Infof("Stopping collector")
+	ctx, cancel := context.WithTimeout(context.Background(), s.conf.ShutdownTimeout)
+	defer cancel()
+	err, stdout, stderr := s.shellout(ctx, constants.SbShutdownBin)
+	s.logger.Debugf("sbshutdown stdout: %s, stderr: %s", stdout, stderr)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("collector didn't stop within %s: %w", s.conf.ShutdownTimeout, err)
+		}
+		return fmt.Errorf("stopping collector failed with: %w", err)
+	}
+	return nil
+}
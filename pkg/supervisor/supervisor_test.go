@@ -0,0 +1,156 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/cerrors"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/config"
+)
+
+func testLogger() logrus.FieldLogger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 0, want: 5 * time.Second},
+		{attempt: 1, want: 10 * time.Second},
+		{attempt: 2, want: 20 * time.Second},
+		{attempt: 3, want: 40 * time.Second},
+		{attempt: 4, want: time.Minute},
+		{attempt: 10, want: time.Minute},
+		{attempt: 100, want: time.Minute},
+	}
+	s := &Supervisor{conf: &config.RunConfig{RestartBackoff: 5 * time.Second, RestartMaxBackoff: time.Minute}}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("attempt %d", tt.attempt), func(t *testing.T) {
+			if got := s.backoff(tt.attempt); got != tt.want {
+				t.Errorf("backoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckRestartsUpToLimit(t *testing.T) {
+	tests := []struct {
+		name         string
+		restartLimit int
+		threshold    int
+		// checks of dead process before it is reported crashed
+		wantChecks int
+	}{
+		{name: "no restarts", restartLimit: 0, threshold: 1, wantChecks: 1},
+		{name: "one restart", restartLimit: 1, threshold: 1, wantChecks: 2},
+		{name: "threshold per restart", restartLimit: 2, threshold: 3, wantChecks: 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Supervisor{
+				logger: testLogger(),
+				conf: &config.RunConfig{
+					RestartLimit:      tt.restartLimit,
+					RestartBackoff:    time.Millisecond,
+					RestartMaxBackoff: time.Millisecond,
+				},
+			}
+			p := &Process{
+				Name:             "agent",
+				Bin:              "true",
+				PidFile:          filepath.Join(t.TempDir(), "agent.pid"),
+				FailureThreshold: tt.threshold,
+			}
+			var err error
+			checks := 0
+			for err == nil && checks < 100 {
+				checks++
+				err = s.check(context.Background(), p, cerrors.AgentCrashedError)
+			}
+			if !errors.Is(err, cerrors.AgentCrashedError) {
+				t.Fatalf("check() error = %v, want %v", err, cerrors.AgentCrashedError)
+			}
+			if checks != tt.wantChecks {
+				t.Errorf("crashed after %d checks, want %d", checks, tt.wantChecks)
+			}
+			if p.restarts != tt.restartLimit {
+				t.Errorf("restarts = %d, want %d", p.restarts, tt.restartLimit)
+			}
+			if !p.GaveUp() {
+				t.Error("GaveUp() = false after restart attempts ran out")
+			}
+		})
+	}
+}
+
+func TestCheckResetsRestartsOnceStable(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "agent.pid")
+	if err := os.WriteFile(pidFile, []byte(fmt.Sprintf("%d\n", os.Getpid())), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s := &Supervisor{logger: testLogger(), conf: &config.RunConfig{RestartLimit: 3, RestartMaxBackoff: time.Minute}}
+	p := &Process{Name: "agent", PidFile: pidFile, FailureThreshold: 1, restarts: 2, failures: 1}
+
+	p.restartedAt = time.Now()
+	if err := s.check(context.Background(), p, cerrors.AgentCrashedError); err != nil {
+		t.Fatal(err)
+	}
+	if p.restarts != 2 || p.failures != 0 {
+		t.Errorf("recently restarted: restarts = %d, failures = %d, want 2, 0", p.restarts, p.failures)
+	}
+
+	p.restartedAt = time.Now().Add(-2 * time.Minute)
+	if err := s.check(context.Background(), p, cerrors.AgentCrashedError); err != nil {
+		t.Fatal(err)
+	}
+	if p.restarts != 0 {
+		t.Errorf("stable beyond max backoff: restarts = %d, want 0", p.restarts)
+	}
+}
+
+func TestRestartRunsServiceScriptAsProcessUser(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("switching user requires root")
+	}
+	// t.TempDir parent is not accessible to other users
+	dir, err := os.MkdirTemp("", "supervisor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	if err := os.Chmod(dir, 0o777); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "user")
+	bin := filepath.Join(dir, "logicmonitor-agent")
+	if err := os.WriteFile(bin, []byte("#!/bin/sh\nid -un >> "+out+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	s := &Supervisor{
+		logger: testLogger(),
+		conf:   &config.RunConfig{RestartLimit: 1, RestartBackoff: time.Millisecond, RestartMaxBackoff: time.Millisecond, ProcessUser: "nobody"},
+	}
+	p := &Process{Name: "agent", Bin: bin, PidFile: filepath.Join(dir, "agent.pid"), FailureThreshold: 1}
+	if err := s.check(context.Background(), p, cerrors.AgentCrashedError); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// stop and start
+	if got := string(b); got != "nobody\nnobody\n" {
+		t.Errorf("service script ran as %q, want nobody for stop and start", got)
+	}
+}
@@ -13,7 +13,10 @@ func Cleanup(logger logrus.FieldLogger) error {
 	logger.Debug("Cleaning lock files if any")
 	err := filepath.Walk(constants.LockPath,
 		func(path string, info fs.FileInfo, err error) error {
-			if !info.IsDir() && (filepath.Ext(path) != ".lck" || filepath.Ext(path) != ".pid") {
+			if err != nil {
+				return err
+			}
+			if !info.IsDir() && (filepath.Ext(path) == ".lck" || filepath.Ext(path) == ".pid") {
 				err := os.Remove(path)
 				if err != nil {
 					return err
@@ -0,0 +1,49 @@
+package util
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vkumbhar94/lm-bootstrap-collector/pkg/constants"
+)
+
+func TestCleanupRemovesOnlyLockAndPidFiles(t *testing.T) {
+	constants.SetInstallPath(t.TempDir())
+	defer constants.SetInstallPath(constants.DefaultInstallPath)
+	if err := os.MkdirAll(constants.LockPath, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]bool{
+		"agent.lck":                   true,
+		"logicmonitor-agent.java.pid": true,
+		"agent.conf":                  false,
+		"wrapper.log":                 false,
+		"lck":                         false,
+	}
+	for name := range files {
+		if err := os.WriteFile(filepath.Join(constants.LockPath, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := Cleanup(logrus.New()); err != nil {
+		t.Fatalf("Cleanup() failed with: %s", err)
+	}
+	for name, removed := range files {
+		_, err := os.Stat(filepath.Join(constants.LockPath, name))
+		if gone := errors.Is(err, os.ErrNotExist); gone != removed {
+			t.Errorf("%s removed = %v, want %v", name, gone, removed)
+		}
+	}
+}
+
+func TestCleanupMissingLockPath(t *testing.T) {
+	constants.SetInstallPath(filepath.Join(t.TempDir(), "missing"))
+	defer constants.SetInstallPath(constants.DefaultInstallPath)
+	if err := Cleanup(logrus.New()); err == nil {
+		t.Error("Cleanup() of missing lock path must fail")
+	}
+}
@@ -2,9 +2,11 @@ package util
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
+	"os/user"
 )
 
 func Shellout(command string, args ...string) (error, string, string) {
@@ -20,6 +22,69 @@ func Shellout(command string, args ...string) (error, string, string) {
 	return nil, stdout.String(), stderr.String()
 }
 
+// ShelloutEnv same as Shellout, runs command with additional environment variables
+func ShelloutEnv(env []string, command string, args ...string) (error, string, string) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd := exec.Command(command, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("shell error: %w: stdout: %s\n stderr: %s", err, stdout.String(), stderr.String()), stdout.String(), stderr.String()
+	}
+	return nil, stdout.String(), stderr.String()
+}
+
+// ShelloutContext same as Shellout, kills command when context is done
+func ShelloutContext(ctx context.Context, command string, args ...string) (error, string, string) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return fmt.Errorf("shell error: %w: stdout: %s\n stderr: %s", err, stdout.String(), stderr.String()), stdout.String(), stderr.String()
+	}
+	return nil, stdout.String(), stderr.String()
+}
+
+// ShelloutAsContext same as ShelloutContext, runs command as given user with user's HOME, USER and LOGNAME,
+// runs as current user when username is empty
+func ShelloutAsContext(ctx context.Context, username string, command string, args ...string) (error, string, string) {
+	if username == "" {
+		return ShelloutContext(ctx, command, args...)
+	}
+	u, err := user.Lookup(username)
+	if err != nil {
+		return err, "", ""
+	}
+	attr, err := userSysProcAttr(u)
+	if err != nil {
+		return err, "", ""
+	}
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.SysProcAttr = attr
+	cmd.Env = append(os.Environ(), "HOME="+u.HomeDir, "USER="+u.Username, "LOGNAME="+u.Username)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return fmt.Errorf("shell error: %w: stdout: %s\n stderr: %s", err, stdout.String(), stderr.String()), stdout.String(), stderr.String()
+	}
+	return nil, stdout.String(), stderr.String()
+}
+
 func Touch(file string) error {
 	f, err := os.OpenFile(file, os.O_RDONLY|os.O_CREATE, 0o666)
 	if err != nil {
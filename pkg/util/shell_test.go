@@ -0,0 +1,57 @@
+package util
+
+import (
+	"context"
+	"os"
+	"os/user"
+	"strings"
+	"testing"
+)
+
+func TestShelloutAsContext(t *testing.T) {
+	current, err := user.Current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		username string
+		want     string
+		root     bool
+	}{
+		{name: "current user", want: current.Username},
+		{name: "other user", username: "nobody", want: "nobody", root: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.root && os.Geteuid() != 0 {
+				t.Skip("switching user requires root")
+			}
+			if tt.username != "" {
+				if _, err := user.Lookup(tt.username); err != nil {
+					t.Skipf("user %s not available: %s", tt.username, err)
+				}
+			}
+			err, stdout, _ := ShelloutAsContext(context.Background(), tt.username, "id", "-un")
+			if err != nil {
+				t.Fatalf("ShelloutAsContext() failed with: %s", err)
+			}
+			if got := strings.TrimSpace(stdout); got != tt.want {
+				t.Errorf("ran as %q, want %s", got, tt.want)
+			}
+			if tt.username == "" {
+				return
+			}
+			_, stdout, _ = ShelloutAsContext(context.Background(), tt.username, "sh", "-c", "echo $USER")
+			if got := strings.TrimSpace(stdout); got != tt.want {
+				t.Errorf("USER = %q, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShelloutAsContextUnknownUser(t *testing.T) {
+	if err, _, _ := ShelloutAsContext(context.Background(), "no-such-user-lmbc", "true"); err == nil {
+		t.Error("ShelloutAsContext() of unknown user must fail")
+	}
+}
@@ -0,0 +1,32 @@
+//go:build !windows
+
+package util
+
+import (
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// userSysProcAttr process attributes running command as given user with its primary and supplementary groups
+func userSysProcAttr(u *user.User) (*syscall.SysProcAttr, error) {
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	var groups []uint32
+	if ids, err := u.GroupIds(); err == nil {
+		for _, id := range ids {
+			if g, err := strconv.ParseUint(id, 10, 32); err == nil {
+				groups = append(groups, uint32(g))
+			}
+		}
+	}
+	return &syscall.SysProcAttr{
+		Credential: &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid), Groups: groups},
+	}, nil
+}
@@ -0,0 +1,12 @@
+package util
+
+import (
+	"fmt"
+	"os/user"
+	"syscall"
+)
+
+// userSysProcAttr running command as another user is not supported on windows
+func userSysProcAttr(u *user.User) (*syscall.SysProcAttr, error) {
+	return nil, fmt.Errorf("running command as user %s is not supported on windows", u.Username)
+}
@@ -2,9 +2,12 @@ package util
 
 import (
 	"errors"
+	"io"
 	"math"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 func ToSI(size int64) string {
@@ -39,3 +42,91 @@ func FileExists(path string) (bool, error) {
 		return false, err
 	}
 }
+
+// CopyFile copies src to dst keeping src permissions, dst is replaced
+func CopyFile(src string, dst string) error {
+	r, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	stat, err := r.Stat()
+	if err != nil {
+		return err
+	}
+	w, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, stat.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, r); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// Levenshtein edit distance between two strings, case-insensitive
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(strings.ToLower(a)), []rune(strings.ToLower(b))
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// minNearMatchLength shortest candidate considered for near matches, shorter ones are contained in almost any target
+const minNearMatchLength = 3
+
+// NearMatches returns at most n candidates closest to target, containing target or within few edits of it.
+// Empty and very short candidates are skipped.
+func NearMatches(target string, candidates []string, n int) []string {
+	type scored struct {
+		s        string
+		distance int
+	}
+	lowerTarget := strings.ToLower(target)
+	maxDistance := len(target)/3 + 1
+	var matches []scored
+	for _, c := range candidates {
+		if len([]rune(strings.TrimSpace(c))) < minNearMatchLength {
+			continue
+		}
+		d := Levenshtein(target, c)
+		if d <= maxDistance || strings.Contains(strings.ToLower(c), lowerTarget) || strings.Contains(lowerTarget, strings.ToLower(c)) {
+			matches = append(matches, scored{s: c, distance: d})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].distance < matches[j].distance
+	})
+	var result []string
+	for i := 0; i < len(matches) && i < n; i++ {
+		result = append(result, matches[i].s)
+	}
+	return result
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
@@ -0,0 +1,47 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNearMatches(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		candidates []string
+		want       []string
+	}{
+		{
+			name:       "typo",
+			target:     "Primary On-Call",
+			candidates: []string{"Primary On Call", "Secondary", "Database"},
+			want:       []string{"Primary On Call"},
+		},
+		{
+			name:       "candidate contains target",
+			target:     "prod",
+			candidates: []string{"prod-collector-1", "staging"},
+			want:       []string{"prod-collector-1"},
+		},
+		{
+			name:       "empty and short candidates skipped",
+			target:     "Primary On-Call",
+			candidates: []string{"", " ", "a", "On"},
+			want:       nil,
+		},
+		{
+			name:       "at most n closest first",
+			target:     "collector",
+			candidates: []string{"collector-100", "collectors", "collecter", "collector-1"},
+			want:       []string{"collectors", "collecter"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NearMatches(tt.target, tt.candidates, 2); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NearMatches() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}